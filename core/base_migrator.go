@@ -2,13 +2,23 @@ package core
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"log/slog"
+	"time"
 )
 
 // BaseMigratorAbstractMethods defines the methods that must be implemented by a concrete migrator.
 type BaseMigratorAbstractMethods interface {
 	Connect(ctx context.Context) error
+	// GetSchema returns the schema of a version, or an error matching ErrSchemaNotFound
+	// if the version has never been applied.
 	GetSchema(ctx context.Context, version string) (*Schema, error)
+	ListSchemas(ctx context.Context) ([]Schema, error)
+	// SaveSchema creates or replaces the schema of a version, without executing its migration.
+	SaveSchema(ctx context.Context, schema Schema) error
+	// DeleteSchema deletes the schema of a version, without executing its migration.
+	// Deleting a version which has no schema is not an error.
+	DeleteSchema(ctx context.Context, version string) error
 	ApplyMigration(ctx context.Context, mi Migration) error
 	RevertMigration(ctx context.Context, mi Migration) error
 }
@@ -18,6 +28,28 @@ type BaseMigratorAbstractMethods interface {
 type BaseMigrator struct {
 	BaseMigratorAbstractMethods
 	Migrations []Migration
+
+	// Locker guards Up and Down against concurrent runs. Locking is disabled when it is nil.
+	Locker Locker
+	// LockTTL is the lease duration of the lock, it is refreshed while a run is in progress.
+	// Default by DefaultLockTTL.
+	LockTTL time.Duration
+	// LockOwner identifies this migrator in the lock. The runs with the same owner share the lock.
+	// Default by "hostname:pid:random", with a random suffix for each run.
+	LockOwner string
+	// OutOfOrder is the policy for pending migrations which sort before the latest applied version.
+	// Default by OutOfOrderWarn.
+	OutOfOrder OutOfOrderPolicy
+	// AllowIrreversible lets the down runs cross the irreversible migrations:
+	// their schemas are deleted, but their changes are left in the database.
+	AllowIrreversible bool
+	// Observers are notified of the events of the runs.
+	Observers []Observer
+	// Logger receives the records of the runs and of their migrations. Default by slog.Default().
+	Logger *slog.Logger
+	// AppVersion is the build version of the application, recorded in the schemas.
+	// Default by the version of the main module, or its VCS revision.
+	AppVersion string
 }
 
 var _ Gomiger = (*BaseMigrator)(nil)
@@ -32,51 +64,88 @@ func (b *BaseMigrator) isVersionExists(version string) bool {
 }
 
 // Up updates the database to a specific version.
-func (b *BaseMigrator) Up(ctx context.Context, toVersion string) error {
-	if toVersion != "" && !b.isVersionExists(toVersion) {
-		return fmt.Errorf("version %s does not exist", toVersion)
+func (b *BaseMigrator) Up(ctx context.Context, toVersion string) (err error) {
+	if err := b.validateTarget(DirectionUp, toVersion); err != nil {
+		return err
 	}
-	for _, mi := range b.Migrations {
-		schema, err := b.GetSchema(ctx, mi.Version)
-		if err != nil {
-			return fmt.Errorf("failed to get schema: %w", err)
-		}
-		if schema.Status == Applied || schema.Status == Dirty {
-			continue
-		}
-		if err := b.ApplyMigration(ctx, mi); err != nil {
-			return fmt.Errorf("failed to apply migration %s: %w", mi.Version, err)
-		}
-		if mi.Version == toVersion {
-			return nil
-		}
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+	plan, err := b.Plan(ctx, DirectionUp, toVersion)
+	if err != nil {
+		return err
+	}
+	return b.execute(ctx, plan)
+}
+
+// UpSteps applies the next n pending migrations.
+func (b *BaseMigrator) UpSteps(ctx context.Context, n int) (err error) {
+	if err := validateSteps(DirectionUp, n); err != nil {
+		return err
+	}
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+	plan, err := b.PlanSteps(ctx, DirectionUp, n)
+	if err != nil {
+		return err
 	}
-	return nil
+	return b.execute(ctx, plan)
 }
 
 // Down reverts the database to a specific version.
-func (b *BaseMigrator) Down(ctx context.Context, atVersion string) error {
-	if atVersion == "" {
-		return fmt.Errorf("a version is required")
+func (b *BaseMigrator) Down(ctx context.Context, atVersion string) (err error) {
+	if err := b.validateTarget(DirectionDown, atVersion); err != nil {
+		return err
 	}
-	if !b.isVersionExists(atVersion) {
-		return fmt.Errorf("version %s does not exist", atVersion)
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
 	}
-	for i := len(b.Migrations) - 1; i >= 0; i-- {
-		mi := b.Migrations[i]
-		schema, err := b.GetSchema(ctx, mi.Version)
-		if err != nil {
-			return fmt.Errorf("failed to get schema: %w", err)
-		}
-		if schema.Status != Applied && schema.Status != Dirty {
-			continue
-		}
-		if err := b.RevertMigration(ctx, mi); err != nil {
-			return fmt.Errorf("failed to revert migration %s: %w", mi.Version, err)
-		}
-		if mi.Version == atVersion {
+	defer func() { err = errors.Join(err, unlock()) }()
+	plan, err := b.Plan(ctx, DirectionDown, atVersion)
+	if err != nil {
+		return err
+	}
+	return b.execute(ctx, plan)
+}
+
+// DownSteps reverts the last n applied or dirty migrations.
+func (b *BaseMigrator) DownSteps(ctx context.Context, n int) (err error) {
+	if err := validateSteps(DirectionDown, n); err != nil {
+		return err
+	}
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+	plan, err := b.PlanSteps(ctx, DirectionDown, n)
+	if err != nil {
+		return err
+	}
+	return b.execute(ctx, plan)
+}
+
+// revertible returns the migration to revert. When the irreversible migrations are allowed,
+// their down only deletes the schema: a nil Down, or a Down failing with ErrIrreversible, succeeds.
+func (b *BaseMigrator) revertible(mi Migration) Migration {
+	if !b.AllowIrreversible {
+		return mi
+	}
+	down := mi.Down
+	mi.Down = func(ctx context.Context) error {
+		if down == nil {
 			return nil
 		}
+		if err := down(ctx); !errors.Is(err, ErrIrreversible) {
+			return err
+		}
+		return nil
 	}
-	return nil
+	return mi
 }
@@ -24,6 +24,22 @@ func (m *MockAbstractMethods) GetSchema(ctx context.Context, version string) (*S
 	return schema, args.Error(1)
 }
 
+func (m *MockAbstractMethods) ListSchemas(ctx context.Context) ([]Schema, error) {
+	args := m.Called(ctx)
+	schemas, _ := args.Get(0).([]Schema)
+	return schemas, args.Error(1)
+}
+
+func (m *MockAbstractMethods) SaveSchema(ctx context.Context, schema Schema) error {
+	args := m.Called(ctx, schema)
+	return args.Error(0)
+}
+
+func (m *MockAbstractMethods) DeleteSchema(ctx context.Context, version string) error {
+	args := m.Called(ctx, version)
+	return args.Error(0)
+}
+
 func (m *MockAbstractMethods) ApplyMigration(ctx context.Context, mi Migration) error {
 	args := m.Called(ctx, mi)
 	return args.Error(0)
@@ -34,6 +50,9 @@ func (m *MockAbstractMethods) RevertMigration(ctx context.Context, mi Migration)
 	return args.Error(0)
 }
 
+// noopMutation is the Down of the reversible test migrations.
+func noopMutation(context.Context) error { return nil }
+
 type BaseMigratorTestSuite struct {
 	suite.Suite
 	migrator *BaseMigrator
@@ -43,9 +62,9 @@ func (s *BaseMigratorTestSuite) SetupTest() {
 	s.migrator = &BaseMigrator{
 		BaseMigratorAbstractMethods: &MockAbstractMethods{},
 		Migrations: []Migration{
-			{Version: "20240101_initial"},
-			{Version: "20240201_add_users"},
-			{Version: "20240301_add_orders"},
+			{Version: "20240101_initial", Down: noopMutation},
+			{Version: "20240201_add_users", Down: noopMutation},
+			{Version: "20240301_add_orders", Down: noopMutation},
 		},
 	}
 }
@@ -85,7 +104,8 @@ func (s *BaseMigratorTestSuite) TestUp_GetSchemaError() {
 func (s *BaseMigratorTestSuite) TestUp_ApplyMigrationError() {
 	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
 	errApplyMigration := fmt.Errorf("apply migration failed")
-	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{}, nil).Once()
+	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Times(2)
+	mockMethods.On("ListSchemas", mock.Anything).Return([]Schema{}, nil).Once()
 	mockMethods.On("ApplyMigration", mock.Anything, mock.Anything).Return(errApplyMigration).Once()
 
 	err := s.migrator.Up(context.Background(), "20240201_add_users")
@@ -97,7 +117,8 @@ func (s *BaseMigratorTestSuite) TestUp_ApplyMigrationError() {
 func (s *BaseMigratorTestSuite) TestUp_SuccessfulMigration() {
 	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
 	mockMethods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: Applied}, nil).Once()
-	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: InProgress}, nil).Times(2)
+	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Times(2)
+	mockMethods.On("ListSchemas", mock.Anything).Return([]Schema{{Version: "20240301_add_orders", Status: Applied}}, nil).Once()
 	mockMethods.On("ApplyMigration", mock.Anything, mock.Anything).Return(nil).Times(2)
 
 	err := s.migrator.Up(context.Background(), "")
@@ -107,7 +128,8 @@ func (s *BaseMigratorTestSuite) TestUp_SuccessfulMigration() {
 
 func (s *BaseMigratorTestSuite) TestUp_SuccessfulMigrationToVersion() {
 	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
-	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: InProgress}, nil).Times(2)
+	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Times(2)
+	mockMethods.On("ListSchemas", mock.Anything).Return([]Schema{}, nil).Once()
 	mockMethods.On("ApplyMigration", mock.Anything, mock.Anything).Return(nil).Times(2)
 
 	err := s.migrator.Up(context.Background(), "20240201_add_users")
@@ -115,6 +137,18 @@ func (s *BaseMigratorTestSuite) TestUp_SuccessfulMigrationToVersion() {
 	mockMethods.AssertExpectations(s.T())
 }
 
+func (s *BaseMigratorTestSuite) TestUp_SchemaNotFoundIsPending() {
+	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
+	mockMethods.On("GetSchema", mock.Anything, "20240101_initial").Return(&Schema{Status: Applied}, nil).Once()
+	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("wrapped: %w", ErrSchemaNotFound)).Times(2)
+	mockMethods.On("ListSchemas", mock.Anything).Return([]Schema{{Version: "20240101_initial", Status: Applied}}, nil).Once()
+	mockMethods.On("ApplyMigration", mock.Anything, mock.Anything).Return(nil).Times(2)
+
+	err := s.migrator.Up(context.Background(), "")
+	s.NoError(err)
+	mockMethods.AssertExpectations(s.T())
+}
+
 func (s *BaseMigratorTestSuite) TestDown_EmptyVersion() {
 	err := s.migrator.Down(context.Background(), "")
 	s.Error(err)
@@ -143,7 +177,7 @@ func (s *BaseMigratorTestSuite) TestDown_RevertMigrationError() {
 	errRevertMigration := fmt.Errorf("revert migration failed")
 	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{
 		Status: Applied,
-	}, nil).Once()
+	}, nil).Times(2)
 	mockMethods.On("RevertMigration", mock.Anything, mock.Anything).Return(errRevertMigration).Once()
 
 	err := s.migrator.Down(context.Background(), "20240201_add_users")
@@ -167,6 +201,129 @@ func (s *BaseMigratorTestSuite) TestDown_SuccessfulReversion() {
 	mockMethods.AssertExpectations(s.T())
 }
 
+func (s *BaseMigratorTestSuite) TestDown_SchemaNotFoundIsSkipped() {
+	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
+	mockMethods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(nil, ErrSchemaNotFound).Once()
+	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: Applied}, nil).Times(2)
+	mockMethods.On("RevertMigration", mock.Anything, mock.Anything).Return(nil).Times(2)
+
+	err := s.migrator.Down(context.Background(), "20240101_initial")
+	s.NoError(err)
+	mockMethods.AssertNumberOfCalls(s.T(), "RevertMigration", 2)
+	mockMethods.AssertExpectations(s.T())
+}
+
+func (s *BaseMigratorTestSuite) TestSteps_InvalidNumber() {
+	err := s.migrator.UpSteps(context.Background(), 0)
+	s.ErrorContains(err, "the number of steps must be positive, got 0")
+	err = s.migrator.DownSteps(context.Background(), -1)
+	s.ErrorContains(err, "the number of steps must be positive, got -1")
+}
+
+func (s *BaseMigratorTestSuite) TestUpSteps_AppliesTheNextMigrations() {
+	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
+	mockMethods.On("GetSchema", mock.Anything, "20240101_initial").Return(&Schema{Status: Applied}, nil).Once()
+	mockMethods.On("GetSchema", mock.Anything, "20240201_add_users").Return(nil, ErrSchemaNotFound).Once()
+	mockMethods.On("ListSchemas", mock.Anything).Return([]Schema{{Version: "20240101_initial", Status: Applied}}, nil).Once()
+	mockMethods.On("ApplyMigration", mock.Anything, mock.MatchedBy(func(mi Migration) bool {
+		return mi.Version == "20240201_add_users"
+	})).Return(nil).Once()
+
+	err := s.migrator.UpSteps(context.Background(), 1)
+	s.NoError(err)
+	mockMethods.AssertExpectations(s.T())
+}
+
+func (s *BaseMigratorTestSuite) TestDownSteps_RevertsTheLastMigrations() {
+	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
+	mockMethods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(nil, ErrSchemaNotFound).Once()
+	mockMethods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Dirty}, nil).Once()
+	mockMethods.On("GetSchema", mock.Anything, "20240101_initial").Return(&Schema{Status: Applied}, nil).Once()
+	reverted := []string{}
+	mockMethods.On("RevertMigration", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		mi, _ := args.Get(1).(Migration)
+		reverted = append(reverted, mi.Version)
+	}).Return(nil).Times(2)
+
+	err := s.migrator.DownSteps(context.Background(), 5)
+	s.NoError(err)
+	s.Equal([]string{"20240201_add_users", "20240101_initial"}, reverted)
+	mockMethods.AssertExpectations(s.T())
+}
+
+func (s *BaseMigratorTestSuite) TestDownSteps_RevertMigrationError() {
+	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
+	errRevertMigration := fmt.Errorf("revert migration failed")
+	mockMethods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: Applied}, nil).Once()
+	mockMethods.On("RevertMigration", mock.Anything, mock.Anything).Return(errRevertMigration).Once()
+
+	err := s.migrator.DownSteps(context.Background(), 1)
+	s.ErrorIs(err, errRevertMigration)
+	mockMethods.AssertExpectations(s.T())
+}
+
+func (s *BaseMigratorTestSuite) TestDown_IrreversibleRefused() {
+	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
+	s.migrator.Migrations[2].Down = nil
+	mockMethods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: Applied}, nil).Once()
+
+	err := s.migrator.Down(context.Background(), "20240201_add_users")
+	s.ErrorIs(err, ErrIrreversible)
+	s.ErrorContains(err, "20240301_add_orders has no down")
+	mockMethods.AssertNotCalled(s.T(), "RevertMigration", mock.Anything, mock.Anything)
+	mockMethods.AssertExpectations(s.T())
+}
+
+func (s *BaseMigratorTestSuite) TestDown_IrreversibleDownRestoresTheSchema() {
+	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
+	s.migrator.Migrations[2].Down = func(ctx context.Context) error { return ErrIrreversible }
+	applied := &Schema{Version: "20240301_add_orders", Status: Applied, Checksum: "sha256:orders"}
+	mockMethods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(applied, nil).Once()
+	// The plugin marks the migration as dirty, as its down failed.
+	mockMethods.On("RevertMigration", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		mi, _ := args.Get(1).(Migration)
+		s.ErrorIs(mi.Down(context.Background()), ErrIrreversible)
+	}).Return(ErrIrreversible).Once()
+	mockMethods.On("SaveSchema", mock.Anything, *applied).Return(nil).Once()
+
+	err := s.migrator.DownSteps(context.Background(), 1)
+	s.ErrorIs(err, ErrIrreversible)
+	mockMethods.AssertExpectations(s.T())
+}
+
+func (s *BaseMigratorTestSuite) TestDown_IrreversibleAllowed() {
+	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
+	s.migrator.AllowIrreversible = true
+	s.migrator.Migrations[2].Down = nil
+	s.migrator.Migrations[1].Down = func(ctx context.Context) error { return fmt.Errorf("cannot restore: %w", ErrIrreversible) }
+	mockMethods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: Applied}, nil).Times(2)
+	// The downs of the irreversible migrations succeed, so their schemas are deleted.
+	mockMethods.On("RevertMigration", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		mi, _ := args.Get(1).(Migration)
+		s.NoError(mi.Down(context.Background()))
+	}).Return(nil).Times(2)
+
+	err := s.migrator.Down(context.Background(), "20240201_add_users")
+	s.NoError(err)
+	mockMethods.AssertExpectations(s.T())
+}
+
+func (s *BaseMigratorTestSuite) TestDown_AllowedKeepsDownErrors() {
+	mockMethods := s.migrator.BaseMigratorAbstractMethods.(*MockAbstractMethods)
+	s.migrator.AllowIrreversible = true
+	errDown := fmt.Errorf("down failed")
+	s.migrator.Migrations[2].Down = func(ctx context.Context) error { return errDown }
+	mockMethods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: Applied}, nil).Once()
+	mockMethods.On("RevertMigration", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		mi, _ := args.Get(1).(Migration)
+		s.ErrorIs(mi.Down(context.Background()), errDown)
+	}).Return(errDown).Once()
+
+	err := s.migrator.Down(context.Background(), "20240301_add_orders")
+	s.ErrorIs(err, errDown)
+	mockMethods.AssertExpectations(s.T())
+}
+
 func TestBaseMigratorTestSuite(t *testing.T) {
 	suite.Run(t, new(BaseMigratorTestSuite))
 }
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Baseline records the migrations until the version, inclusive, as applied without executing them,
+// e.g. to adopt gomiger on a database whose changes were made by hand.
+// The schemas are marked as baselined. The versions which are already applied are left untouched.
+func (b *BaseMigrator) Baseline(ctx context.Context, version string) (err error) {
+	if !b.isVersionExists(version) {
+		return fmt.Errorf("version %s does not exist", version)
+	}
+	migrations, err := SortMigrations(b.Migrations)
+	if err != nil {
+		return err
+	}
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+	// Check every version before writing, so a failed baseline records nothing.
+	pending := []Migration{}
+	for _, mi := range migrations {
+		schema, err := b.GetSchema(ctx, mi.Version)
+		if err != nil && !errors.Is(err, ErrSchemaNotFound) {
+			return fmt.Errorf("failed to get schema: %w", err)
+		}
+		if schema != nil && schema.Status != Applied {
+			return fmt.Errorf("version %s is %s, recover it before the baseline", mi.Version, schema.Status)
+		}
+		if schema == nil {
+			pending = append(pending, mi)
+		}
+		if mi.Version == version {
+			break
+		}
+	}
+	for _, mi := range pending {
+		baseline := Schema{Version: mi.Version, Timestamp: time.Now(), Status: Applied, Checksum: mi.Checksum, Baselined: true}
+		b.execution("").Stamp(&baseline, nil)
+		if err := b.SaveSchema(ctx, baseline); err != nil {
+			return fmt.Errorf("failed to baseline version %s: %w", mi.Version, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BaselineTestSuite struct {
+	suite.Suite
+	store    *fakeStore
+	migrator *BaseMigrator
+}
+
+func (s *BaselineTestSuite) SetupTest() {
+	s.store = &fakeStore{schemas: map[string]Schema{}}
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.store,
+		Migrations: []Migration{
+			{Version: "20240101_initial", Checksum: "sha256:initial"},
+			{Version: "20240201_add_users"},
+			{Version: "20240301_add_orders"},
+		},
+	}
+}
+
+func (s *BaselineTestSuite) TestBaseline_NonexistentVersion() {
+	err := s.migrator.Baseline(context.Background(), "20240401_nonexistent")
+	s.ErrorContains(err, "version 20240401_nonexistent does not exist")
+}
+
+func (s *BaselineTestSuite) TestBaseline_RecordsWithoutExecuting() {
+	s.Require().NoError(s.migrator.Baseline(context.Background(), "20240201_add_users"))
+	s.Empty(s.store.history)
+	s.Len(s.store.schemas, 2)
+	initial := s.store.schemas["20240101_initial"]
+	s.Equal(Applied, initial.Status)
+	s.True(initial.Baselined)
+	s.Equal("sha256:initial", initial.Checksum)
+	s.True(s.store.schemas["20240201_add_users"].Baselined)
+
+	// The next Up only runs the migrations after the baseline.
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	s.Equal([]string{"up 20240301_add_orders"}, s.store.history)
+	s.False(s.store.schemas["20240301_add_orders"].Baselined)
+
+	statuses, err := s.migrator.Status(context.Background())
+	s.Require().NoError(err)
+	s.True(statuses[0].Baselined)
+	s.False(statuses[2].Baselined)
+}
+
+func (s *BaselineTestSuite) TestBaseline_KeepsAppliedVersions() {
+	s.store.schemas["20240101_initial"] = Schema{Version: "20240101_initial", Status: Applied}
+
+	s.Require().NoError(s.migrator.Baseline(context.Background(), "20240201_add_users"))
+	s.False(s.store.schemas["20240101_initial"].Baselined)
+	s.True(s.store.schemas["20240201_add_users"].Baselined)
+}
+
+func (s *BaselineTestSuite) TestBaseline_DirtyVersion() {
+	s.store.schemas["20240101_initial"] = Schema{Version: "20240101_initial", Status: Dirty}
+
+	err := s.migrator.Baseline(context.Background(), "20240201_add_users")
+	s.ErrorContains(err, "version 20240101_initial is dirty")
+	s.NotContains(s.store.schemas, "20240201_add_users")
+}
+
+func (s *BaselineTestSuite) TestBaseline_DirtyVersionInTheRangeRecordsNothing() {
+	s.store.schemas["20240201_add_users"] = Schema{Version: "20240201_add_users", Status: Dirty}
+
+	err := s.migrator.Baseline(context.Background(), "20240301_add_orders")
+	s.ErrorContains(err, "version 20240201_add_users is dirty")
+	s.NotContains(s.store.schemas, "20240101_initial")
+	s.NotContains(s.store.schemas, "20240301_add_orders")
+}
+
+func TestBaselineTestSuite(t *testing.T) {
+	suite.Run(t, new(BaselineTestSuite))
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"fmt"
+)
+
+// ChecksumMismatch is an applied migration whose code changed since it was applied.
+type ChecksumMismatch struct {
+	Version string `json:"version"`
+	// Recorded is the checksum stored in the schema when the migration was applied.
+	Recorded string `json:"recorded"`
+	// Current is the checksum of the migration in the code.
+	Current string `json:"current"`
+}
+
+// Validate checks the dependencies of the migrations, then lists the applied migrations whose code checksum
+// does not match the checksum of their schema.
+// Migrations without checksum, in the code or in the store, are not validated.
+func (b *BaseMigrator) Validate(ctx context.Context) ([]ChecksumMismatch, error) {
+	if _, err := SortMigrations(b.Migrations); err != nil {
+		return nil, err
+	}
+	schemas, err := b.ListSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	schemaByVersion := make(map[string]*Schema, len(schemas))
+	for i := range schemas {
+		schemaByVersion[schemas[i].Version] = &schemas[i]
+	}
+
+	mismatches := []ChecksumMismatch{}
+	for _, mi := range b.Migrations {
+		schema, ok := schemaByVersion[mi.Version]
+		if !ok || schema.Status != Applied {
+			continue
+		}
+		if mi.Checksum == "" || schema.Checksum == "" || mi.Checksum == schema.Checksum {
+			continue
+		}
+		mismatches = append(mismatches, ChecksumMismatch{
+			Version:  mi.Version,
+			Recorded: schema.Checksum,
+			Current:  mi.Checksum,
+		})
+	}
+	return mismatches, nil
+}
+
+func (b *BaseMigrator) checksumOf(version string) string {
+	for _, mi := range b.Migrations {
+		if mi.Version == version {
+			return mi.Checksum
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChecksumTestSuite struct {
+	suite.Suite
+	methods  *MockAbstractMethods
+	migrator *BaseMigrator
+}
+
+func (s *ChecksumTestSuite) SetupTest() {
+	s.methods = &MockAbstractMethods{}
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.methods,
+		Migrations: []Migration{
+			{Version: "20240101_initial", Checksum: "aaa"},
+			{Version: "20240201_add_users", Checksum: "bbb"},
+			{Version: "20240301_add_orders", Checksum: "ccc"},
+			{Version: "20240401_no_checksum"},
+		},
+	}
+}
+
+func (s *ChecksumTestSuite) TestValidate_NoDrift() {
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{
+		{Version: "20240101_initial", Status: Applied, Checksum: "aaa"},
+		{Version: "20240201_add_users", Status: Applied, Checksum: "bbb"},
+	}, nil).Once()
+
+	mismatches, err := s.migrator.Validate(context.Background())
+	s.NoError(err)
+	s.Empty(mismatches)
+}
+
+func (s *ChecksumTestSuite) TestValidate_Drift() {
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{
+		{Version: "20240101_initial", Status: Applied, Checksum: "aaa"},
+		{Version: "20240201_add_users", Status: Applied, Checksum: "old"},
+	}, nil).Once()
+
+	mismatches, err := s.migrator.Validate(context.Background())
+	s.NoError(err)
+	s.Equal([]ChecksumMismatch{{Version: "20240201_add_users", Recorded: "old", Current: "bbb"}}, mismatches)
+}
+
+func (s *ChecksumTestSuite) TestValidate_SkipsUnverifiable() {
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{
+		// Applied before the checksums were recorded.
+		{Version: "20240101_initial", Status: Applied},
+		// Not applied.
+		{Version: "20240201_add_users", Status: Dirty, Checksum: "old"},
+		// No checksum in the code.
+		{Version: "20240401_no_checksum", Status: Applied, Checksum: "old"},
+		// Orphaned.
+		{Version: "20231201_removed", Status: Applied, Checksum: "old"},
+	}, nil).Once()
+
+	mismatches, err := s.migrator.Validate(context.Background())
+	s.NoError(err)
+	s.Empty(mismatches)
+}
+
+func (s *ChecksumTestSuite) TestValidate_ListSchemasError() {
+	errList := fmt.Errorf("list failed")
+	s.methods.On("ListSchemas", mock.Anything).Return(nil, errList).Once()
+
+	_, err := s.migrator.Validate(context.Background())
+	s.ErrorIs(err, errList)
+}
+
+func (s *ChecksumTestSuite) TestValidate_InvalidDependencies() {
+	s.migrator.Migrations[1].DependsOn = []string{"20231201_removed"}
+
+	_, err := s.migrator.Validate(context.Background())
+	s.ErrorContains(err, "depends on version 20231201_removed which does not exist")
+	s.methods.AssertNotCalled(s.T(), "ListSchemas", mock.Anything)
+}
+
+func (s *ChecksumTestSuite) TestForce_RecordsChecksum() {
+	s.methods.On("SaveSchema", mock.Anything, mock.MatchedBy(func(schema Schema) bool {
+		return schema.Version == "20240201_add_users" && schema.Checksum == "bbb"
+	})).Return(nil).Once()
+
+	s.NoError(s.migrator.Force(context.Background(), "20240201_add_users", StateApplied))
+	s.methods.AssertExpectations(s.T())
+}
+
+func TestChecksumTestSuite(t *testing.T) {
+	suite.Run(t, new(ChecksumTestSuite))
+}
@@ -0,0 +1,34 @@
+// Package main is the code generation tool of gomiger, to run after the migrations are edited.
+//
+// It loads the configuration file (gomiger.rc) and regenerates the registry file of the
+// migrations, so the registry always matches the migration code: every migration is
+// registered, sorted by version, with the checksum of its code.
+//
+// This tool is typically called by a go:generate directive:
+//
+//	//go:generate go run github.com/ParteeLabs/gomiger/core/cmd/gomiger-gen
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/generator"
+)
+
+func main() {
+	rcPath := flag.String("rc-path", "./gomiger.rc.yaml", "Path to the gomiger.rc file")
+	flag.Parse()
+
+	rc, err := core.GetGomigerRC(*rcPath)
+	if err != nil {
+		log.Fatalf("Cannot load the gomiger.rc file: %s", err)
+	}
+	if !generator.IsSrcCodeInitialized(rc) {
+		log.Fatalf("The source code is NOT INITIALIZED")
+	}
+	if err := generator.GenRegistryFile(rc); err != nil {
+		log.Fatalf("Cannot generate the registry file: %s", err)
+	}
+}
@@ -20,6 +20,22 @@ type GomigerConfig struct {
 	URI string `yaml:"uri"`
 	// The path to the table / collection schema store.
 	SchemaStore string `yaml:"schema_store"`
+	// The policy for pending migrations which sort before the latest applied version: strict, warn or allow.
+	// Default by warn.
+	OutOfOrder OutOfOrderPolicy `yaml:"out_of_order"`
+	// The database plugin of the migrator scaffolded by gomiger-init: sqlite, postgres or mysql.
+	// Default by none, the migrator implements the database methods itself.
+	Plugin string `yaml:"plugin"`
+	// Run each migration with its schema update in a database transaction, read by mongomiger only.
+	// sqliteminger & pgminger always use transactions, mysqlminger never does.
+	// A migration opts out with Migration.DisableTransaction.
+	Transactional bool `yaml:"transactional"`
+	// Let the down runs cross the irreversible migrations, by deleting their schemas without reverting them.
+	// It is not read from the gomiger.rc file, but set for a single run, e.g. by --allow-irreversible.
+	AllowIrreversible bool `yaml:"-"`
+	// The observers of the runs, in addition to the observers registered by the migrator.
+	// They are not read from the gomiger.rc file, but set for a single run, e.g. by --metrics-textfile.
+	Observers []Observer `yaml:"-"`
 }
 
 var (
@@ -57,6 +73,9 @@ func (rc *GomigerConfig) PopulateAndValidate() error {
 	if rc.PkgName == "" {
 		rc.PkgName = filepath.Dir(rc.Path)
 	}
+	if err := rc.OutOfOrder.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -125,6 +125,18 @@ func TestGomigerConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("PopulateAndValidate with out of order policy", func(t *testing.T) {
+		config := &GomigerConfig{OutOfOrder: OutOfOrderStrict}
+		if err := config.PopulateAndValidate(); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+
+		config = &GomigerConfig{OutOfOrder: "sometimes"}
+		if err := config.PopulateAndValidate(); err == nil {
+			t.Error("Expected error for unknown out of order policy")
+		}
+	})
+
 	t.Run("GetGomigerRC with URI from environment", func(t *testing.T) {
 		tempFile, cleanup := createTempConfigFile(t, validConfigContent)
 		defer cleanup()
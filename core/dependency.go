@@ -0,0 +1,78 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortMigrations returns the migrations in a topological order of their dependencies.
+// A migration comes after all the migrations it depends on, the others keep their declared order,
+// so the order is deterministic. It fails on a missing dependency or a dependency cycle.
+func SortMigrations(migrations []Migration) ([]Migration, error) {
+	declared := make(map[string]bool, len(migrations))
+	for _, mi := range migrations {
+		declared[mi.Version] = true
+	}
+	for _, mi := range migrations {
+		for _, dependency := range mi.DependsOn {
+			if !declared[dependency] {
+				return nil, fmt.Errorf("migration %s depends on version %s which does not exist", mi.Version, dependency)
+			}
+		}
+	}
+
+	sorted := make([]Migration, 0, len(migrations))
+	done := make(map[string]bool, len(migrations))
+	isReady := func(mi Migration) bool {
+		for _, dependency := range mi.DependsOn {
+			if !done[dependency] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(sorted) < len(migrations) {
+		next := -1
+		// Take the first ready migration in the declared order.
+		for i, mi := range migrations {
+			if !done[mi.Version] && isReady(mi) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, fmt.Errorf("dependency cycle between migrations: %s", findCycle(migrations, done))
+		}
+		sorted = append(sorted, migrations[next])
+		done[migrations[next].Version] = true
+	}
+	return sorted, nil
+}
+
+// findCycle follows the pending dependencies from the first blocked migration until a version repeats.
+// Every blocked migration has a pending dependency, so the walk always ends in a cycle.
+func findCycle(migrations []Migration, done map[string]bool) string {
+	byVersion := make(map[string]Migration, len(migrations))
+	var current string
+	for _, mi := range migrations {
+		byVersion[mi.Version] = mi
+		if current == "" && !done[mi.Version] {
+			current = mi.Version
+		}
+	}
+	path := []string{}
+	seenAt := map[string]int{}
+	for {
+		if i, ok := seenAt[current]; ok {
+			return strings.Join(append(path[i:], current), " -> ")
+		}
+		seenAt[current] = len(path)
+		path = append(path, current)
+		for _, dependency := range byVersion[current].DependsOn {
+			if !done[dependency] {
+				current = dependency
+				break
+			}
+		}
+	}
+}
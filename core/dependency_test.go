@@ -0,0 +1,83 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DependencyTestSuite struct {
+	suite.Suite
+}
+
+func (s *DependencyTestSuite) versionsOf(migrations []Migration) []string {
+	versions := make([]string, 0, len(migrations))
+	for _, mi := range migrations {
+		versions = append(versions, mi.Version)
+	}
+	return versions
+}
+
+func (s *DependencyTestSuite) TestSortMigrations_NoDependencies() {
+	sorted, err := SortMigrations([]Migration{
+		{Version: "20240101_initial"},
+		{Version: "20240201_add_users"},
+		{Version: "20240301_add_orders"},
+	})
+	s.NoError(err)
+	s.Equal([]string{"20240101_initial", "20240201_add_users", "20240301_add_orders"}, s.versionsOf(sorted))
+}
+
+func (s *DependencyTestSuite) TestSortMigrations_DependencyDeclaredLater() {
+	sorted, err := SortMigrations([]Migration{
+		{Version: "20240101_initial"},
+		{Version: "20240201_add_orders", DependsOn: []string{"20240301_add_users"}},
+		{Version: "20240301_add_users"},
+		{Version: "20240401_add_products"},
+	})
+	s.NoError(err)
+	s.Equal([]string{"20240101_initial", "20240301_add_users", "20240201_add_orders", "20240401_add_products"}, s.versionsOf(sorted))
+}
+
+func (s *DependencyTestSuite) TestSortMigrations_Deterministic() {
+	migrations := []Migration{
+		{Version: "d", DependsOn: []string{"b", "c"}},
+		{Version: "c", DependsOn: []string{"a"}},
+		{Version: "b", DependsOn: []string{"a"}},
+		{Version: "a"},
+	}
+	for range 10 {
+		sorted, err := SortMigrations(migrations)
+		s.NoError(err)
+		s.Equal([]string{"a", "c", "b", "d"}, s.versionsOf(sorted))
+	}
+}
+
+func (s *DependencyTestSuite) TestSortMigrations_MissingDependency() {
+	_, err := SortMigrations([]Migration{
+		{Version: "20240101_initial"},
+		{Version: "20240201_add_users", DependsOn: []string{"20231201_removed"}},
+	})
+	s.ErrorContains(err, "migration 20240201_add_users depends on version 20231201_removed which does not exist")
+}
+
+func (s *DependencyTestSuite) TestSortMigrations_Cycle() {
+	_, err := SortMigrations([]Migration{
+		{Version: "20240101_initial"},
+		{Version: "20240201_add_users", DependsOn: []string{"20240301_add_orders"}},
+		{Version: "20240301_add_orders", DependsOn: []string{"20240201_add_users"}},
+		{Version: "20240401_add_products", DependsOn: []string{"20240301_add_orders"}},
+	})
+	s.ErrorContains(err, "dependency cycle between migrations: 20240201_add_users -> 20240301_add_orders -> 20240201_add_users")
+}
+
+func (s *DependencyTestSuite) TestSortMigrations_SelfDependency() {
+	_, err := SortMigrations([]Migration{
+		{Version: "20240101_initial", DependsOn: []string{"20240101_initial"}},
+	})
+	s.ErrorContains(err, "dependency cycle between migrations: 20240101_initial -> 20240101_initial")
+}
+
+func TestDependencyTestSuite(t *testing.T) {
+	suite.Run(t, new(DependencyTestSuite))
+}
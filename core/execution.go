@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"runtime/debug"
+	"sync"
+)
+
+// modulePath is the module of gomiger, whose version is recorded in the schemas.
+const modulePath = "github.com/ParteeLabs/gomiger/core"
+
+// processInfo is the metadata of the running process, recorded in the schemas.
+type processInfo struct {
+	appliedBy      string
+	gomigerVersion string
+	appVersion     string
+}
+
+// process reads the metadata of the running process once.
+var process = sync.OnceValue(func() (p processInfo) {
+	username, hostname := "unknown", "unknown"
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+	if h, err := os.Hostname(); err == nil {
+		hostname = h
+	}
+	p.appliedBy = username + "@" + hostname
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return p
+	}
+	p.appVersion = info.Main.Version
+	if p.appVersion == "" || p.appVersion == "(devel)" {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				p.appVersion = setting.Value
+			}
+		}
+	}
+	if info.Main.Path == modulePath {
+		p.gomigerVersion = info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			p.gomigerVersion = dep.Version
+		}
+	}
+	return p
+})
+
+// Execution is the metadata of the execution of a migration, recorded by the plugins in the schema they write.
+type Execution struct {
+	// AppliedBy is the user & hostname of the process, e.g. deploy@ci-runner-1.
+	AppliedBy      string
+	GomigerVersion string
+	AppVersion     string
+	Direction      Direction
+}
+
+// executionKey is the context key of the execution of the running migration.
+type executionKey struct{}
+
+// execution returns the execution of a migration by this migrator.
+func (b *BaseMigrator) execution(direction Direction) Execution {
+	p := process()
+	execution := Execution{AppliedBy: p.appliedBy, GomigerVersion: p.gomigerVersion, AppVersion: p.appVersion, Direction: direction}
+	if b.AppVersion != "" {
+		execution.AppVersion = b.AppVersion
+	}
+	return execution
+}
+
+// withExecution returns a copy of the context carrying the execution of a migration.
+func withExecution(ctx context.Context, execution Execution) context.Context {
+	return context.WithValue(ctx, executionKey{}, execution)
+}
+
+// ExecutionFromContext returns the execution of the migration passed to ApplyMigration or RevertMigration.
+// It is zero outside of a migration.
+func ExecutionFromContext(ctx context.Context) Execution {
+	execution, _ := ctx.Value(executionKey{}).(Execution)
+	return execution
+}
+
+// Stamp records the execution in a schema, with the error of a failed execution.
+// The plugins stamp the schema in the write which records the status of the migration, e.g. dirty.
+func (e Execution) Stamp(schema *Schema, err error) {
+	schema.AppliedBy, schema.GomigerVersion, schema.AppVersion = e.AppliedBy, e.GomigerVersion, e.AppVersion
+	schema.Direction, schema.Error = e.Direction, ""
+	if err != nil {
+		schema.Error = err.Error()
+	}
+}
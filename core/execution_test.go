@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ExecutionTestSuite struct {
+	suite.Suite
+	store    *fakeStore
+	migrator *BaseMigrator
+}
+
+func (s *ExecutionTestSuite) SetupTest() {
+	s.store = &fakeStore{schemas: map[string]Schema{}}
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.store,
+		Migrations: []Migration{
+			{Version: "20240101_initial", Down: noopMutation},
+			{Version: "20240201_add_users", Down: noopMutation},
+		},
+	}
+}
+
+func (s *ExecutionTestSuite) TestUp_RecordsTheExecution() {
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+
+	for _, schema := range s.store.schemas {
+		s.Equal(Applied, schema.Status)
+		s.Contains(schema.AppliedBy, "@")
+		s.Equal(DirectionUp, schema.Direction)
+		s.Empty(schema.Error)
+	}
+}
+
+func (s *ExecutionTestSuite) TestUp_AppVersion() {
+	s.migrator.AppVersion = "v1.2.3"
+
+	s.Require().NoError(s.migrator.Up(context.Background(), "20240101_initial"))
+	s.Equal("v1.2.3", s.store.schemas["20240101_initial"].AppVersion)
+}
+
+func (s *ExecutionTestSuite) TestUp_RecordsTheErrorOfDirtyMigrations() {
+	s.store.failWith = fmt.Errorf("duplicate key")
+
+	s.Error(s.migrator.Up(context.Background(), ""))
+	schema := s.store.schemas["20240101_initial"]
+	s.Equal(Dirty, schema.Status)
+	s.Equal(DirectionUp, schema.Direction)
+	s.Equal("duplicate key", schema.Error)
+}
+
+func (s *ExecutionTestSuite) TestRetry_ClearsTheError() {
+	s.store.failWith = fmt.Errorf("duplicate key")
+	s.Error(s.migrator.Up(context.Background(), ""))
+	s.store.failWith = nil
+
+	s.Require().NoError(s.migrator.Retry(context.Background(), "20240101_initial"))
+	schema := s.store.schemas["20240101_initial"]
+	s.Equal(Applied, schema.Status)
+	s.Empty(schema.Error)
+}
+
+func (s *ExecutionTestSuite) TestDown_RecordsTheErrorOfDirtyMigrations() {
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	s.store.revertFailWith = fmt.Errorf("table is locked")
+
+	s.Error(s.migrator.DownSteps(context.Background(), 1))
+	schema := s.store.schemas["20240201_add_users"]
+	s.Equal(Dirty, schema.Status)
+	s.Equal(DirectionDown, schema.Direction)
+	s.Equal("table is locked", schema.Error)
+	s.Equal(DirectionUp, s.store.schemas["20240101_initial"].Direction)
+}
+
+func (s *ExecutionTestSuite) TestBaseline_RecordsTheProcess() {
+	s.Require().NoError(s.migrator.Baseline(context.Background(), "20240101_initial"))
+	s.Contains(s.store.schemas["20240101_initial"].AppliedBy, "@")
+}
+
+func (s *ExecutionTestSuite) TestForce_RecordsTheProcess() {
+	s.migrator.AppVersion = "v1.2.3"
+
+	s.Require().NoError(s.migrator.Force(context.Background(), "20240201_add_users", StateApplied))
+	schema := s.store.schemas["20240201_add_users"]
+	s.Contains(schema.AppliedBy, "@")
+	s.Equal("v1.2.3", schema.AppVersion)
+}
+
+func (s *ExecutionTestSuite) TestExecutionFromContext_OutsideOfAMigration() {
+	s.Zero(ExecutionFromContext(context.Background()))
+}
+
+func TestExecutionTestSuite(t *testing.T) {
+	suite.Run(t, new(ExecutionTestSuite))
+}
@@ -0,0 +1,25 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// ComputeChecksums computes the checksum of every migration in the folder, keyed by version.
+// A checksum is the SHA-256 of the formatted Up & Down bodies, so comments and blank lines do not change it.
+func ComputeChecksums(path string) (map[string]string, error) {
+	migrations, err := scanMigrations(path)
+	if err != nil {
+		return nil, err
+	}
+	checksums := make(map[string]string, len(migrations))
+	for _, mi := range migrations {
+		checksums[mi.Version] = checksumOf(mi)
+	}
+	return checksums, nil
+}
+
+func checksumOf(mi *migrationDecl) string {
+	sum := sha256.Sum256([]byte("Up:\n" + mi.Bodies["Up"] + "\nDown:\n" + mi.Bodies["Down"]))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const checksumTestMigration = `package migrations
+
+import "context"
+
+func (m *Migrator) Migration_202401010000_create_users_Up(ctx context.Context) error {
+	return m.create(ctx, "users")
+}
+
+func (m *Migrator) Migration_202401010000_create_users_Down(ctx context.Context) error {
+	return m.drop(ctx, "users")
+}
+
+func (m *Migrator) Migration_202401010000_create_users_Version() string {
+	return "202401010000"
+}
+`
+
+func writeChecksumTestMigration(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "202401010000_create_users.mg.go"), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write the migration file: %v", err)
+	}
+}
+
+func TestComputeChecksums(t *testing.T) {
+	t.Run("computes a checksum per version", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChecksumTestMigration(t, dir, checksumTestMigration)
+
+		checksums, err := ComputeChecksums(dir)
+		if err != nil {
+			t.Fatalf("ComputeChecksums failed: %v", err)
+		}
+		if len(checksums) != 1 {
+			t.Fatalf("Expected 1 checksum, got: %d", len(checksums))
+		}
+		if len(checksums["202401010000"]) != 64 {
+			t.Errorf("Expected a SHA-256 hex checksum, got: %q", checksums["202401010000"])
+		}
+	})
+
+	t.Run("ignores comments and blank lines", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChecksumTestMigration(t, dir, checksumTestMigration)
+		before, err := ComputeChecksums(dir)
+		if err != nil {
+			t.Fatalf("ComputeChecksums failed: %v", err)
+		}
+
+		commented := strings.Replace(checksumTestMigration, `	return m.create(ctx, "users")`, "\t// Create the users.\n\n\treturn m.create(ctx, \"users\")", 1)
+		writeChecksumTestMigration(t, dir, commented)
+		after, err := ComputeChecksums(dir)
+		if err != nil {
+			t.Fatalf("ComputeChecksums failed: %v", err)
+		}
+		if before["202401010000"] != after["202401010000"] {
+			t.Error("Expected the checksum to ignore comments and blank lines")
+		}
+	})
+
+	t.Run("changes when the code changes", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChecksumTestMigration(t, dir, checksumTestMigration)
+		before, err := ComputeChecksums(dir)
+		if err != nil {
+			t.Fatalf("ComputeChecksums failed: %v", err)
+		}
+
+		writeChecksumTestMigration(t, dir, strings.Replace(checksumTestMigration, `m.drop(ctx, "users")`, `m.drop(ctx, "accounts")`, 1))
+		after, err := ComputeChecksums(dir)
+		if err != nil {
+			t.Fatalf("ComputeChecksums failed: %v", err)
+		}
+		if before["202401010000"] == after["202401010000"] {
+			t.Error("Expected the checksum to change with the Down code")
+		}
+	})
+
+	t.Run("returns error for invalid source", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChecksumTestMigration(t, dir, "package migrations\nfunc {")
+
+		if _, err := ComputeChecksums(dir); err == nil {
+			t.Error("Expected error for invalid source")
+		}
+	})
+}
@@ -4,7 +4,19 @@ package generator
 var MigrationScriptTemplateBase64 = `cGFja2FnZSBtYWluCgppbXBvcnQgKAoJImNvbnRleHQiCikKCi8vbm9saW50OmdvZG9jbGludCxyZXZpdmUKZnVuYyAobSAqTWlncmF0b3IpIE1pZ3JhdGlvbk5hbWVVcChjdHggY29udGV4dC5Db250ZXh0KSBlcnJvciB7CgkvKiogWW91ciBtaWdyYXRpb24gdXAgY29kZSBoZXJlOiAqLwoJcmV0dXJuIG5pbAp9CgovL25vbGludDpnb2RvY2xpbnQscmV2aXZlCmZ1bmMgKG0gKk1pZ3JhdG9yKSBNaWdyYXRpb25OYW1lRG93bihjdHggY29udGV4dC5Db250ZXh0KSBlcnJvciB7CgkvKiogWW91ciBtaWdyYXRpb24gZG93biBjb2RlIGhlcmU6ICovCglyZXR1cm4gbmlsCn0KCi8vIEFVVE8gR0VORVJBVEVELCBETyBOT1QgTU9ESUZZIQovLwovL25vbGludDpnb2RvY2xpbnQKZnVuYyAobSAqTWlncmF0b3IpIE1pZ3JhdGlvbk5hbWVWZXJzaW9uKCkgc3RyaW5nIHsKCXJldHVybiAiX19WRVJTSU9OX18iCn0K`
 
 //nolint:revive
-var MigratorTemplateBase64 = `cGFja2FnZSBtYWluCgppbXBvcnQgKAoJImdpdGh1Yi5jb20vUGFydGVlTGFicy9nb21pZ2VyL2NvcmUiCikKCi8vIE1pZ3JhdG9yIGlzIHRoZSBtYWluIG1pZ3JhdG9yIHN0cnVjdC4KdHlwZSBNaWdyYXRvciBzdHJ1Y3QgewoJLy8gQmFzZU1pZ3JhdG9yIGRvc2VzIG5vdCBpbnZvbHZlIHRvIGFueSBkYXRhYmFzZS4gVXNlIG91ciBwbHVnaW5zIHRvIGNvbm5lY3QgdG8geW91ciBkYXRhYmFzZS4KCS8vIE9yIG92ZXJyaWRlIENvbm5lY3QsIEdldFNjaGVtYSwgQXBwbHlNaWdyYXRpb24sIFJldmVydE1pZ3JhdGlvbiBtZXRob2RzIHRvIGltcGxlbWVudCB3aXRoIHlvdXIgZGF0YWJhc2UuCgkqY29yZS5CYXNlTWlncmF0b3IKCgkvLyAqbW9uZ29taWdlci5Nb25nb21pZ2VyCglDb25maWcgKmNvcmUuR29taWdlckNvbmZpZwp9CgovLyBOZXdNaWdyYXRvciBjcmVhdGVzIGEgbmV3IG1pZ3JhdG9yLgpmdW5jIE5ld01pZ3JhdG9yKGNvbmZpZyAqY29yZS5Hb21pZ2VyQ29uZmlnKSBjb3JlLkdvbWlnZXIgewoJbSA6PSAmTWlncmF0b3J7CgkJLy8gTW9uZ29taWdlcjogbW9uZ29taWdlci5OZXdNb25nb21pZ2VyKGNvbmZpZyksCgkJQ29uZmlnOiBjb25maWcsCgl9CgoJLy8gKiogQWRkIHlvdXIgbWlncmF0aW9ucyBoZXJlICoqCgltLk1pZ3JhdGlvbnMgPSBbXWNvcmUuTWlncmF0aW9uewoJCS8vIHtWZXJzaW9uOiBNaWdyYXRpb25OYW1lVmVyc2lvbigpLCBVcDogbS5NaWdyYXRpb25OYW1lVXAsIERvd246IG0uTWlncmF0aW9uTmFtZURvd259LAoJfQoJcmV0dXJuIG0KfQo=`
+var IrreversibleMigrationScriptTemplateBase64 = `cGFja2FnZSBtYWluCgppbXBvcnQgKAoJImNvbnRleHQiCikKCi8vbm9saW50OmdvZG9jbGludCxyZXZpdmUKZnVuYyAobSAqTWlncmF0b3IpIE1pZ3JhdGlvbk5hbWVVcChjdHggY29udGV4dC5Db250ZXh0KSBlcnJvciB7CgkvKiogWW91ciBtaWdyYXRpb24gdXAgY29kZSBoZXJlOiAqLwoJcmV0dXJuIG5pbAp9CgovLyBUaGlzIG1pZ3JhdGlvbiBpcyBpcnJldmVyc2libGU6IGl0IGhhcyBubyBkb3duIG1ldGhvZC4KLy8gRG93biBydW5zIHJlZnVzZSB0byBjcm9zcyBpdCwgdW5sZXNzIHRoZXkgYXJlIHJ1biB3aXRoIC0tYWxsb3ctaXJyZXZlcnNpYmxlLgoKLy8gQVVUTyBHRU5FUkFURUQsIERPIE5PVCBNT0RJRlkhCi8vCi8vbm9saW50OmdvZG9jbGludApmdW5jIChtICpNaWdyYXRvcikgTWlncmF0aW9uTmFtZVZlcnNpb24oKSBzdHJpbmcgewoJcmV0dXJuICJfX1ZFUlNJT05fXyIKfQo=`
 
 //nolint:revive
-var CliTemplateBase64 = `Ly8gVEhJUyBGSUxFIElTIEdFTkVSQVRFRCBCWSBHT01JR0VSLiBQTEVBU0UgRE8gTk9UIE1PRElGWSBJVC4KLy8KLy9ub2xpbnQ6cmV2aXZlCnBhY2thZ2UgbWFpbgoKaW1wb3J0ICgKCSJjb250ZXh0IgoJImZtdCIKCSJsb2ciCgkib3MiCgoJImdpdGh1Yi5jb20vUGFydGVlTGFicy9nb21pZ2VyL2NvcmUiCgkiZ2l0aHViLmNvbS9QYXJ0ZWVMYWJzL2dvbWlnZXIvY29yZS9nZW5lcmF0b3IiCgkiZ2l0aHViLmNvbS91cmZhdmUvY2xpL3YzIgopCgp2YXIgcmNQYXRoIHN0cmluZwoKLy8gUnVuIHN0YXJ0cyB0aGUgQ0xJCmZ1bmMgUnVuKCkgewoJY21kIDo9ICZjbGkuQ29tbWFuZHsKCQlGbGFnczogW11jbGkuRmxhZ3sKCQkJJmNsaS5TdHJpbmdGbGFnewoJCQkJTmFtZTogICAgICAgICJyYy1wYXRoIiwKCQkJCUNhdGVnb3J5OiAgICAiZ2xvYmFsIiwKCQkJCVZhbHVlOiAgICAgICAiLi9nb21pZ2VyLnJjLnlhbWwiLAoJCQkJVXNhZ2U6ICAgICAgICJQYXRoIHRvIHRoZSBnb21pZ2VyLnJjIGZpbGUiLAoJCQkJRGVzdGluYXRpb246ICZyY1BhdGgsCgkJCX0sCgkJfSwKCQlDb21tYW5kczogW10qY2xpLkNvbW1hbmR7CgkJCW5ld0NtZCwKCQkJbWlncmF0ZVVwQ21kLAoJCQltaWdyYXRlRG93bkNtZCwKCQkJZ2V0TWlncmF0aW9uU3RhdHVzQ21kLAoJCX0sCgl9CglpZiBlcnIgOj0gY21kLlJ1bihjb250ZXh0LkJhY2tncm91bmQoKSwgb3MuQXJncyk7IGVyciAhPSBuaWwgewoJCWxvZy5GYXRhbChlcnIpCgl9Cn0KCnZhciBuZXdDbWQgPSAmY2xpLkNvbW1hbmR7CglOYW1lOiAgICAibmV3IiwKCUFsaWFzZXM6IFtdc3RyaW5neyJuIn0sCglVc2FnZTogICAiZ2VuZXJhdGUgYSBuZXcgbWlncmF0aW9uIiwKCUFjdGlvbjogZnVuYyhfIGNvbnRleHQuQ29udGV4dCwgY21kICpjbGkuQ29tbWFuZCkgZXJyb3IgewoJCXJjLCBlcnIgOj0gY29yZS5HZXRHb21pZ2VyUkMocmNQYXRoKQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZm10LkVycm9yZigiY2Fubm90IGxvYWQgdGhlIGdvbWlnZXIucmMgZmlsZTogJXciLCBlcnIpCgkJfQoJCWlmICFnZW5lcmF0b3IuSXNTcmNDb2RlSW5pdGlhbGl6ZWQocmMpIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoInRoZSBzb3VyY2UgY29kZSBpcyBOT1QgSU5JVElBTElaRUQiKQoJCX0KCQlpZiBlcnIgOj0gZ2VuZXJhdG9yLkdlbk1pZ3JhdGlvbkZpbGUocmMsIGNtZC5BcmdzKCkuR2V0KDApKTsgZXJyICE9IG5pbCB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgZ2VuZXJhdGUgbWlncmF0aW9uIGZpbGU6ICV3IiwgZXJyKQoJCX0KCQlyZXR1cm4gbmlsCgl9LAp9Cgp2YXIgbWlncmF0ZVVwQ21kID0gJmNsaS5Db21tYW5kewoJTmFtZTogICAgInVwIiwKCUFsaWFzZXM6IFtdc3RyaW5neyJtIn0sCglVc2FnZTogICAibWlncmF0ZSB0aGUgZGF0YWJhc2UgdXAgdG8gYSB2ZXJzaW9uIiwKCUFjdGlvbjogZnVuYyhjdHggY29udGV4dC5Db250ZXh0LCBjbWQgKmNsaS5Db21tYW5kKSBlcnJvciB7CgkJcmMsIGVyciA6PSBjb3JlLkdldEdvbWlnZXJSQyhyY1BhdGgpCgkJaWYgZXJyICE9IG5pbCB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgbG9hZCB0aGUgZ29taWdlci5yYyBmaWxlOiAldyIsIGVycikKCQl9CgkJaWYgIWdlbmVyYXRvci5Jc1NyY0NvZGVJbml0aWFsaXplZChyYykgewoJCQlyZXR1cm4gZm10LkVycm9yZigidGhlIHNvdXJjZSBjb2RlIGlzIE5PVCBJTklUSUFMSVpFRCIpCgkJfQoJCW1pZ3JhdG9yIDo9IE5ld01pZ3JhdG9yKCZjb3JlLkdvbWlnZXJDb25maWd7fSkKCQlpZiBlcnIgOj0gbWlncmF0b3IuQ29ubmVjdChjdHgpOyBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoImNhbm5vdCBjb25uZWN0IHRvIGRhdGFiYXNlOiAldyIsIGVycikKCQl9CgkJaWYgZXJyIDo9IG1pZ3JhdG9yLlVwKGN0eCwgY21kLkFyZ3MoKS5HZXQoMCkpOyBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoImNhbm5vdCBtaWdyYXRlIHRoZSBkYXRhYmFzZTogJXciLCBlcnIpCgkJfQoJCXJldHVybiBuaWwKCX0sCn0KCnZhciBtaWdyYXRlRG93bkNtZCA9ICZjbGkuQ29tbWFuZHsKCU5hbWU6ICAgICJkb3duIiwKCUFsaWFzZXM6IFtdc3RyaW5neyJkIn0sCglVc2FnZTogICAibWlncmF0ZSB0aGUgZGF0YWJhc2UgZG93biB0byBhIHZlcnNpb24iLAoJQWN0aW9uOiBmdW5jKGN0eCBjb250ZXh0LkNvbnRleHQsIGNtZCAqY2xpLkNvbW1hbmQpIGVycm9yIHsKCQlyYywgZXJyIDo9IGNvcmUuR2V0R29taWdlclJDKHJjUGF0aCkKCQlpZiBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoImNhbm5vdCBsb2FkIHRoZSBnb21pZ2VyLnJjIGZpbGU6ICV3IiwgZXJyKQoJCX0KCQlpZiAhZ2VuZXJhdG9yLklzU3JjQ29kZUluaXRpYWxpemVkKHJjKSB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJ0aGUgc291cmNlIGNvZGUgaXMgTk9UIElOSVRJQUxJWkVEIikKCQl9CgkJbWlncmF0b3IgOj0gTmV3TWlncmF0b3IoJmNvcmUuR29taWdlckNvbmZpZ3t9KQoJCWlmIGVyciA6PSBtaWdyYXRvci5Db25uZWN0KGN0eCk7IGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZm10LkVycm9yZigiY2Fubm90IGNvbm5lY3QgdG8gZGF0YWJhc2U6ICV3IiwgZXJyKQoJCX0KCQlpZiBlcnIgOj0gbWlncmF0b3IuRG93bihjdHgsIGNtZC5BcmdzKCkuR2V0KDApKTsgZXJyICE9IG5pbCB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgbWlncmF0ZSB0aGUgZGF0YWJhc2U6ICV3IiwgZXJyKQoJCX0KCQlyZXR1cm4gbmlsCgl9LAp9Cgp2YXIgZ2V0TWlncmF0aW9uU3RhdHVzQ21kID0gJmNsaS5Db21tYW5kewoJTmFtZTogICAgInN0YXR1cyIsCglBbGlhc2VzOiBbXXN0cmluZ3sicyJ9LAoJVXNhZ2U6ICAgImdldCB0aGUgY3VycmVudCBtaWdyYXRpb24gc3RhdHVzIiwKCUFjdGlvbjogZnVuYyhjdHggY29udGV4dC5Db250ZXh0LCBjbWQgKmNsaS5Db21tYW5kKSBlcnJvciB7CgkJcmMsIGVyciA6PSBjb3JlLkdldEdvbWlnZXJSQyhyY1BhdGgpCgkJaWYgZXJyICE9IG5pbCB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgbG9hZCB0aGUgZ29taWdlci5yYyBmaWxlOiAldyIsIGVycikKCQl9CgkJaWYgIWdlbmVyYXRvci5Jc1NyY0NvZGVJbml0aWFsaXplZChyYykgewoJCQlyZXR1cm4gZm10LkVycm9yZigidGhlIHNvdXJjZSBjb2RlIGlzIE5PVCBJTklUSUFMSVpFRCIpCgkJfQoJCW1pZ3JhdG9yIDo9IE5ld01pZ3JhdG9yKCZjb3JlLkdvbWlnZXJDb25maWd7fSkKCQlpZiBlcnIgOj0gbWlncmF0b3IuQ29ubmVjdChjdHgpOyBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoImNhbm5vdCBjb25uZWN0IHRvIGRhdGFiYXNlOiAldyIsIGVycikKCQl9CgkJc2NoZW1hLCBlcnIgOj0gbWlncmF0b3IuR2V0U2NoZW1hKGN0eCwgY21kLkFyZ3MoKS5HZXQoMCkpCgkJaWYgZXJyICE9IG5pbCB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgZ2V0IHNjaGVtYTogJXciLCBlcnIpCgkJfQoJCWZtdC5QcmludGYoIlZlcnNpb246ICVzLCBTdGF0dXM6ICVzXG4iLCBzY2hlbWEuVmVyc2lvbiwgc2NoZW1hLlN0YXR1cykKCQlyZXR1cm4gbmlsCgl9LAp9Cg==`
+var MigratorTemplateBase64 = `cGFja2FnZSBtYWluCgppbXBvcnQgKAoJImdpdGh1Yi5jb20vUGFydGVlTGFicy9nb21pZ2VyL2NvcmUiCikKCi8vIE1pZ3JhdG9yIGlzIHRoZSBtYWluIG1pZ3JhdG9yIHN0cnVjdC4KdHlwZSBNaWdyYXRvciBzdHJ1Y3QgewoJLy8gQmFzZU1pZ3JhdG9yIGRvc2VzIG5vdCBpbnZvbHZlIHRvIGFueSBkYXRhYmFzZS4gVXNlIG91ciBwbHVnaW5zIHRvIGNvbm5lY3QgdG8geW91ciBkYXRhYmFzZS4KCS8vIE9yIG92ZXJyaWRlIENvbm5lY3QsIEdldFNjaGVtYSwgQXBwbHlNaWdyYXRpb24sIFJldmVydE1pZ3JhdGlvbiBtZXRob2RzIHRvIGltcGxlbWVudCB3aXRoIHlvdXIgZGF0YWJhc2UuCgkqY29yZS5CYXNlTWlncmF0b3IKCgkvLyAqbW9uZ29taWdlci5Nb25nb21pZ2VyCglDb25maWcgKmNvcmUuR29taWdlckNvbmZpZwp9CgovLyBOZXdNaWdyYXRvciBjcmVhdGVzIGEgbmV3IG1pZ3JhdG9yLgpmdW5jIE5ld01pZ3JhdG9yKGNvbmZpZyAqY29yZS5Hb21pZ2VyQ29uZmlnKSBjb3JlLkdvbWlnZXIgewoJbSA6PSAmTWlncmF0b3J7CgkJLy8gTW9uZ29taWdlcjogbW9uZ29taWdlci5OZXdNb25nb21pZ2VyKGNvbmZpZyksCgkJQ29uZmlnOiBjb25maWcsCgl9CgoJLy8gVGhlIG1pZ3JhdGlvbnMgYXJlIHJlZ2lzdGVyZWQgYnkgdGhlIGdlbmVyYXRvciBpbiByZWdpc3RyeS5tZy5nbywKCS8vIG9uIHRoZSBgbmV3YCAmIGBnZW5lcmF0ZWAgY29tbWFuZHMuCgltLk1pZ3JhdGlvbnMgPSBtLnJlZ2lzdGVyZWRNaWdyYXRpb25zKCkKCXJldHVybiBtCn0K`
+
+//nolint:revive
+var SqliteMigratorTemplateBase64 = `cGFja2FnZSBtYWluCgppbXBvcnQgKAoJImdpdGh1Yi5jb20vUGFydGVlTGFicy9nb21pZ2VyL2NvcmUiCgkiZ2l0aHViLmNvbS9QYXJ0ZWVMYWJzL2dvbWlnZXIvc3FsaXRlbWluZ2VyIgopCgovLyBNaWdyYXRvciBpcyB0aGUgbWFpbiBtaWdyYXRvciBzdHJ1Y3QuCnR5cGUgTWlncmF0b3Igc3RydWN0IHsKCS8vIFNxbGl0ZW1pbmdlciBydW5zIGVhY2ggbWlncmF0aW9uIGluIGEgdHJhbnNhY3Rpb24uCgkvLyBRdWVyeSB0aGUgZGF0YWJhc2Ugd2l0aCBtLkNvbm4oY3R4KSBpbiB0aGUgbWlncmF0aW9ucywgc28gdGhlIHF1ZXJpZXMgYXJlIHBhcnQgb2YgaXQuCgkqc3FsaXRlbWluZ2VyLlNxbGl0ZW1pbmdlcgoKCUNvbmZpZyAqY29yZS5Hb21pZ2VyQ29uZmlnCn0KCi8vIE5ld01pZ3JhdG9yIGNyZWF0ZXMgYSBuZXcgbWlncmF0b3IuCmZ1bmMgTmV3TWlncmF0b3IoY29uZmlnICpjb3JlLkdvbWlnZXJDb25maWcpIGNvcmUuR29taWdlciB7CgltIDo9ICZNaWdyYXRvcnsKCQlTcWxpdGVtaW5nZXI6IHNxbGl0ZW1pbmdlci5OZXdTcWxpdGVtaW5nZXIoY29uZmlnKSwKCQlDb25maWc6ICAgICAgIGNvbmZpZywKCX0KCgkvLyBUaGUgbWlncmF0aW9ucyBhcmUgcmVnaXN0ZXJlZCBieSB0aGUgZ2VuZXJhdG9yIGluIHJlZ2lzdHJ5Lm1nLmdvLAoJLy8gb24gdGhlIGBuZXdgICYgYGdlbmVyYXRlYCBjb21tYW5kcy4KCW0uTWlncmF0aW9ucyA9IG0ucmVnaXN0ZXJlZE1pZ3JhdGlvbnMoKQoJcmV0dXJuIG0KfQo=`
+
+//nolint:revive
+var PostgresMigratorTemplateBase64 = `cGFja2FnZSBtYWluCgppbXBvcnQgKAoJImdpdGh1Yi5jb20vUGFydGVlTGFicy9nb21pZ2VyL2NvcmUiCgkiZ2l0aHViLmNvbS9QYXJ0ZWVMYWJzL2dvbWlnZXIvcGdtaW5nZXIiCikKCi8vIE1pZ3JhdG9yIGlzIHRoZSBtYWluIG1pZ3JhdG9yIHN0cnVjdC4KdHlwZSBNaWdyYXRvciBzdHJ1Y3QgewoJLy8gUGdtaW5nZXIgcnVucyBlYWNoIG1pZ3JhdGlvbiBpbiBhIHRyYW5zYWN0aW9uLgoJLy8gUXVlcnkgdGhlIGRhdGFiYXNlIHdpdGggbS5Db25uKGN0eCkgaW4gdGhlIG1pZ3JhdGlvbnMsIHNvIHRoZSBxdWVyaWVzIGFyZSBwYXJ0IG9mIGl0LgoJKnBnbWluZ2VyLlBnbWluZ2VyCgoJQ29uZmlnICpjb3JlLkdvbWlnZXJDb25maWcKfQoKLy8gTmV3TWlncmF0b3IgY3JlYXRlcyBhIG5ldyBtaWdyYXRvci4KZnVuYyBOZXdNaWdyYXRvcihjb25maWcgKmNvcmUuR29taWdlckNvbmZpZykgY29yZS5Hb21pZ2VyIHsKCW0gOj0gJk1pZ3JhdG9yewoJCVBnbWluZ2VyOiBwZ21pbmdlci5OZXdQZ21pbmdlcihjb25maWcpLAoJCUNvbmZpZzogICBjb25maWcsCgl9CgoJLy8gVGhlIG1pZ3JhdGlvbnMgYXJlIHJlZ2lzdGVyZWQgYnkgdGhlIGdlbmVyYXRvciBpbiByZWdpc3RyeS5tZy5nbywKCS8vIG9uIHRoZSBgbmV3YCAmIGBnZW5lcmF0ZWAgY29tbWFuZHMuCgltLk1pZ3JhdGlvbnMgPSBtLnJlZ2lzdGVyZWRNaWdyYXRpb25zKCkKCXJldHVybiBtCn0K`
+
+//nolint:revive
+var MysqlMigratorTemplateBase64 = `cGFja2FnZSBtYWluCgppbXBvcnQgKAoJImdpdGh1Yi5jb20vUGFydGVlTGFicy9nb21pZ2VyL2NvcmUiCgkiZ2l0aHViLmNvbS9QYXJ0ZWVMYWJzL2dvbWlnZXIvbXlzcWxtaW5nZXIiCikKCi8vIE1pZ3JhdG9yIGlzIHRoZSBtYWluIG1pZ3JhdG9yIHN0cnVjdC4KdHlwZSBNaWdyYXRvciBzdHJ1Y3QgewoJLy8gTXlzcWxtaW5nZXIgZG9lcyBub3QgcnVuIHRoZSBtaWdyYXRpb25zIGluIHRyYW5zYWN0aW9ucywgYXMgTXlTUUwgY29tbWl0cyBEREwgc3RhdGVtZW50cyBpbXBsaWNpdGx5LgoJLy8gQSBmYWlsZWQgbWlncmF0aW9uIGlzIG1hcmtlZCBhcyBkaXJ0eSwgcmVjb3ZlciBpdCB3aXRoIHRoZSBmb3JjZSBvciByZXRyeSBjb21tYW5kLgoJKm15c3FsbWluZ2VyLk15c3FsbWluZ2VyCgoJQ29uZmlnICpjb3JlLkdvbWlnZXJDb25maWcKfQoKLy8gTmV3TWlncmF0b3IgY3JlYXRlcyBhIG5ldyBtaWdyYXRvci4KZnVuYyBOZXdNaWdyYXRvcihjb25maWcgKmNvcmUuR29taWdlckNvbmZpZykgY29yZS5Hb21pZ2VyIHsKCW0gOj0gJk1pZ3JhdG9yewoJCU15c3FsbWluZ2VyOiBteXNxbG1pbmdlci5OZXdNeXNxbG1pbmdlcihjb25maWcpLAoJCUNvbmZpZzogICAgICBjb25maWcsCgl9CgoJLy8gVGhlIG1pZ3JhdGlvbnMgYXJlIHJlZ2lzdGVyZWQgYnkgdGhlIGdlbmVyYXRvciBpbiByZWdpc3RyeS5tZy5nbywKCS8vIG9uIHRoZSBgbmV3YCAmIGBnZW5lcmF0ZWAgY29tbWFuZHMuCgltLk1pZ3JhdGlvbnMgPSBtLnJlZ2lzdGVyZWRNaWdyYXRpb25zKCkKCXJldHVybiBtCn0K`
+
+//nolint:revive
+var CliTemplateBase64 = `Ly8gVEhJUyBGSUxFIElTIEdFTkVSQVRFRCBCWSBHT01JR0VSLiBQTEVBU0UgRE8gTk9UIE1PRElGWSBJVC4KLy8KLy9ub2xpbnQ6cmV2aXZlCnBhY2thZ2UgbWFpbgoKaW1wb3J0ICgKCSJjb250ZXh0IgoJImVuY29kaW5nL2pzb24iCgkiZXJyb3JzIgoJImZtdCIKCSJsb2cvc2xvZyIKCSJvcyIKCSJzdHJpbmdzIgoJInRleHQvdGFid3JpdGVyIgoJInRpbWUiCgoJImdpdGh1Yi5jb20vUGFydGVlTGFicy9nb21pZ2VyL2NvcmUiCgkiZ2l0aHViLmNvbS9QYXJ0ZWVMYWJzL2dvbWlnZXIvY29yZS9nZW5lcmF0b3IiCgkiZ2l0aHViLmNvbS9QYXJ0ZWVMYWJzL2dvbWlnZXIvY29yZS9tZXRyaWNzIgoJImdpdGh1Yi5jb20vdXJmYXZlL2NsaS92MyIKKQoKdmFyIHJjUGF0aCBzdHJpbmcKCi8vIFJ1biBzdGFydHMgdGhlIENMSQpmdW5jIFJ1bigpIHsKCWNtZCA6PSAmY2xpLkNvbW1hbmR7CgkJRmxhZ3M6IFtdY2xpLkZsYWd7CgkJCSZjbGkuU3RyaW5nRmxhZ3sKCQkJCU5hbWU6ICAgICAgICAicmMtcGF0aCIsCgkJCQlDYXRlZ29yeTogICAgImdsb2JhbCIsCgkJCQlWYWx1ZTogICAgICAgIi4vZ29taWdlci5yYy55YW1sIiwKCQkJCVVzYWdlOiAgICAgICAiUGF0aCB0byB0aGUgZ29taWdlci5yYyBmaWxlIiwKCQkJCURlc3RpbmF0aW9uOiAmcmNQYXRoLAoJCQl9LAoJCQkmY2xpLlN0cmluZ0ZsYWd7CgkJCQlOYW1lOiAgICAgImxvZy1mb3JtYXQiLAoJCQkJQ2F0ZWdvcnk6ICJnbG9iYWwiLAoJCQkJVmFsdWU6ICAgICJ0ZXh0IiwKCQkJCVVzYWdlOiAgICAiRm9ybWF0IG9mIHRoZSBsb2dzOiB0ZXh0IG9yIGpzb24iLAoJCQl9LAoJCQkmY2xpLlN0cmluZ0ZsYWd7CgkJCQlOYW1lOiAgICAgImxvZy1sZXZlbCIsCgkJCQlDYXRlZ29yeTogImdsb2JhbCIsCgkJCQlWYWx1ZTogICAgImluZm8iLAoJCQkJVXNhZ2U6ICAgICJNaW5pbXVtIGxldmVsIG9mIHRoZSBsb2dzOiBkZWJ1ZywgaW5mbywgd2FybiBvciBlcnJvciIsCgkJCX0sCgkJfSwKCQlCZWZvcmU6IGZ1bmMoY3R4IGNvbnRleHQuQ29udGV4dCwgY21kICpjbGkuQ29tbWFuZCkgKGNvbnRleHQuQ29udGV4dCwgZXJyb3IpIHsKCQkJbG9nZ2VyLCBlcnIgOj0gbmV3TG9nZ2VyKGNtZC5TdHJpbmcoImxvZy1mb3JtYXQiKSwgY21kLlN0cmluZygibG9nLWxldmVsIikpCgkJCWlmIGVyciAhPSBuaWwgewoJCQkJcmV0dXJuIGN0eCwgZXJyCgkJCX0KCQkJc2xvZy5TZXREZWZhdWx0KGxvZ2dlcikKCQkJcmV0dXJuIGN0eCwgbmlsCgkJfSwKCQlDb21tYW5kczogW10qY2xpLkNvbW1hbmR7CgkJCW5ld0NtZCwKCQkJZ2VuZXJhdGVDbWQsCgkJCW1pZ3JhdGVVcENtZCwKCQkJbWlncmF0ZURvd25DbWQsCgkJCXJlZG9DbWQsCgkJCXJlc2V0Q21kLAoJCQlnb3RvQ21kLAoJCQliYXNlbGluZUNtZCwKCQkJZ2V0TWlncmF0aW9uU3RhdHVzQ21kLAoJCQlmb3JjZUNtZCwKCQkJcmV0cnlDbWQsCgkJCXJlcGFpckNtZCwKCQkJdmFsaWRhdGVDbWQsCgkJCWxvY2tDbWQsCgkJCXVubG9ja0NtZCwKCQl9LAoJfQoJaWYgZXJyIDo9IGNtZC5SdW4oY29udGV4dC5CYWNrZ3JvdW5kKCksIG9zLkFyZ3MpOyBlcnIgIT0gbmlsIHsKCQlzbG9nLkVycm9yKCJnb21pZ2VyIGZhaWxlZCIsICJlcnJvciIsIGVycikKCQlvcy5FeGl0KDEpCgl9Cn0KCi8vIG5ld0xvZ2dlciBjcmVhdGVzIHRoZSBsb2dnZXIgb2YgdGhlIG1pZ3JhdG9yLCB3cml0aW5nIHRvIHN0ZGVyci4KZnVuYyBuZXdMb2dnZXIoZm9ybWF0IHN0cmluZywgbGV2ZWwgc3RyaW5nKSAoKnNsb2cuTG9nZ2VyLCBlcnJvcikgewoJdmFyIGx2bCBzbG9nLkxldmVsCglpZiBlcnIgOj0gbHZsLlVubWFyc2hhbFRleHQoW11ieXRlKGxldmVsKSk7IGVyciAhPSBuaWwgewoJCXJldHVybiBuaWwsIGZtdC5FcnJvcmYoInVua25vd24gbG9nIGxldmVsICVzLCBvbmx5IGRlYnVnLCBpbmZvLCB3YXJuIGFuZCBlcnJvciBhcmUgYWxsb3dlZCIsIGxldmVsKQoJfQoJb3B0aW9ucyA6PSAmc2xvZy5IYW5kbGVyT3B0aW9uc3tMZXZlbDogbHZsfQoJc3dpdGNoIGZvcm1hdCB7CgljYXNlICJ0ZXh0IjoKCQlyZXR1cm4gc2xvZy5OZXcoc2xvZy5OZXdUZXh0SGFuZGxlcihvcy5TdGRlcnIsIG9wdGlvbnMpKSwgbmlsCgljYXNlICJqc29uIjoKCQlyZXR1cm4gc2xvZy5OZXcoc2xvZy5OZXdKU09OSGFuZGxlcihvcy5TdGRlcnIsIG9wdGlvbnMpKSwgbmlsCglkZWZhdWx0OgoJCXJldHVybiBuaWwsIGZtdC5FcnJvcmYoInVua25vd24gbG9nIGZvcm1hdCAlcywgb25seSB0ZXh0IGFuZCBqc29uIGFyZSBhbGxvd2VkIiwgZm9ybWF0KQoJfQp9Cgp2YXIgbmV3Q21kID0gJmNsaS5Db21tYW5kewoJTmFtZTogICAgIm5ldyIsCglBbGlhc2VzOiBbXXN0cmluZ3sibiJ9LAoJVXNhZ2U6ICAgImdlbmVyYXRlIGEgbmV3IG1pZ3JhdGlvbiIsCglGbGFnczogW11jbGkuRmxhZ3sKCQkmY2xpLkJvb2xGbGFnewoJCQlOYW1lOiAgImlycmV2ZXJzaWJsZSIsCgkJCVVzYWdlOiAiZ2VuZXJhdGUgYSBtaWdyYXRpb24gd2hpY2ggY2Fubm90IGJlIHVuZG9uZSwgd2l0aG91dCBkb3duIG1ldGhvZCIsCgkJfSwKCX0sCglBY3Rpb246IGZ1bmMoXyBjb250ZXh0LkNvbnRleHQsIGNtZCAqY2xpLkNvbW1hbmQpIGVycm9yIHsKCQlyYywgZXJyIDo9IGNvcmUuR2V0R29taWdlclJDKHJjUGF0aCkKCQlpZiBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoImNhbm5vdCBsb2FkIHRoZSBnb21pZ2VyLnJjIGZpbGU6ICV3IiwgZXJyKQoJCX0KCQlpZiAhZ2VuZXJhdG9yLklzU3JjQ29kZUluaXRpYWxpemVkKHJjKSB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJ0aGUgc291cmNlIGNvZGUgaXMgTk9UIElOSVRJQUxJWkVEIikKCQl9CgkJZ2VuTWlncmF0aW9uRmlsZSA6PSBnZW5lcmF0b3IuR2VuTWlncmF0aW9uRmlsZQoJCWlmIGNtZC5Cb29sKCJpcnJldmVyc2libGUiKSB7CgkJCWdlbk1pZ3JhdGlvbkZpbGUgPSBnZW5lcmF0b3IuR2VuSXJyZXZlcnNpYmxlTWlncmF0aW9uRmlsZQoJCX0KCQlpZiBlcnIgOj0gZ2VuTWlncmF0aW9uRmlsZShyYywgY21kLkFyZ3MoKS5HZXQoMCkpOyBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoImNhbm5vdCBnZW5lcmF0ZSBtaWdyYXRpb24gZmlsZTogJXciLCBlcnIpCgkJfQoJCXJldHVybiBuaWwKCX0sCn0KCnZhciBnZW5lcmF0ZUNtZCA9ICZjbGkuQ29tbWFuZHsKCU5hbWU6ICAiZ2VuZXJhdGUiLAoJVXNhZ2U6ICJyZWdpc3RlciB0aGUgbWlncmF0aW9ucyBvZiB0aGUgc291cmNlIGNvZGUgaW4gdGhlIHJlZ2lzdHJ5IGZpbGUiLAoJQWN0aW9uOiBmdW5jKF8gY29udGV4dC5Db250ZXh0LCBfICpjbGkuQ29tbWFuZCkgZXJyb3IgewoJCXJjLCBlcnIgOj0gY29yZS5HZXRHb21pZ2VyUkMocmNQYXRoKQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZm10LkVycm9yZigiY2Fubm90IGxvYWQgdGhlIGdvbWlnZXIucmMgZmlsZTogJXciLCBlcnIpCgkJfQoJCWlmICFnZW5lcmF0b3IuSXNTcmNDb2RlSW5pdGlhbGl6ZWQocmMpIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoInRoZSBzb3VyY2UgY29kZSBpcyBOT1QgSU5JVElBTElaRUQiKQoJCX0KCQlpZiBlcnIgOj0gZ2VuZXJhdG9yLkdlblJlZ2lzdHJ5RmlsZShyYyk7IGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZm10LkVycm9yZigiY2Fubm90IGdlbmVyYXRlIHRoZSByZWdpc3RyeSBmaWxlOiAldyIsIGVycikKCQl9CgkJcmV0dXJuIG5pbAoJfSwKfQoKdmFyIGRyeVJ1bkZsYWcgPSAmY2xpLkJvb2xGbGFnewoJTmFtZTogICJkcnktcnVuIiwKCVVzYWdlOiAicHJpbnQgdGhlIG1pZ3JhdGlvbnMgdGhhdCB3b3VsZCBiZSBleGVjdXRlZCwgd2l0aG91dCBleGVjdXRpbmcgdGhlbSIsCn0KCnZhciBhbGxvd0lycmV2ZXJzaWJsZUZsYWcgPSAmY2xpLkJvb2xGbGFnewoJTmFtZTogICJhbGxvdy1pcnJldmVyc2libGUiLAoJVXNhZ2U6ICJjcm9zcyB0aGUgaXJyZXZlcnNpYmxlIG1pZ3JhdGlvbnM6IGZvcmdldCB0aGVtIHdpdGhvdXQgcmV2ZXJ0aW5nIHRoZWlyIGNoYW5nZXMiLAp9CgovLyBhbGxvd0lycmV2ZXJzaWJsZSBvdmVycmlkZXMgdGhlIGdvbWlnZXIucmMgZmlsZSB3aXRoIHRoZSAtLWFsbG93LWlycmV2ZXJzaWJsZSBmbGFnLgpmdW5jIGFsbG93SXJyZXZlcnNpYmxlKGNtZCAqY2xpLkNvbW1hbmQpIGZ1bmMocmMgKmNvcmUuR29taWdlckNvbmZpZykgewoJcmV0dXJuIGZ1bmMocmMgKmNvcmUuR29taWdlckNvbmZpZykgewoJCXJjLkFsbG93SXJyZXZlcnNpYmxlID0gY21kLkJvb2woImFsbG93LWlycmV2ZXJzaWJsZSIpCgl9Cn0KCnZhciBzdGVwc0ZsYWcgPSAmY2xpLkludEZsYWd7CglOYW1lOiAgInN0ZXBzIiwKCVVzYWdlOiAibWlncmF0ZSB0aGUgbmV4dCBOIG1pZ3JhdGlvbnMgdXAsIG9yIHRoZSBsYXN0IE4gbWlncmF0aW9ucyBkb3duLCBpbnN0ZWFkIG9mIGdvaW5nIHRvIGEgdmVyc2lvbiIsCn0KCi8vIHN0ZXBzT2YgcmV0dXJucyB0aGUgbnVtYmVyIG9mIHN0ZXBzIG9mIHRoZSBydW4sIHplcm8gd2hlbiBpdCBnb2VzIHRvIGEgdmVyc2lvbi4KZnVuYyBzdGVwc09mKGNtZCAqY2xpLkNvbW1hbmQpIChpbnQsIGVycm9yKSB7CglpZiAhY21kLklzU2V0KCJzdGVwcyIpIHsKCQlyZXR1cm4gMCwgbmlsCgl9CglpZiBjbWQuQXJncygpLlByZXNlbnQoKSB7CgkJcmV0dXJuIDAsIGZtdC5FcnJvcmYoImEgdmVyc2lvbiBhbmQgLS1zdGVwcyBjYW5ub3QgYmUgdXNlZCB0b2dldGhlciIpCgl9CglpZiBjbWQuSW50KCJzdGVwcyIpIDw9IDAgewoJCXJldHVybiAwLCBmbXQuRXJyb3JmKCItLXN0ZXBzIG11c3QgYmUgcG9zaXRpdmUiKQoJfQoJcmV0dXJuIGNtZC5JbnQoInN0ZXBzIiksIG5pbAp9Cgp2YXIgbWlncmF0ZVVwQ21kID0gJmNsaS5Db21tYW5kewoJTmFtZTogICAgInVwIiwKCUFsaWFzZXM6IFtdc3RyaW5neyJtIn0sCglVc2FnZTogICAibWlncmF0ZSB0aGUgZGF0YWJhc2UgdXAgdG8gYSB2ZXJzaW9uIiwKCUZsYWdzOiBbXWNsaS5GbGFnewoJCWRyeVJ1bkZsYWcsCgkJc3RlcHNGbGFnLAoJCSZjbGkuU3RyaW5nRmxhZ3sKCQkJTmFtZTogICJvdXQtb2Ytb3JkZXIiLAoJCQlVc2FnZTogIm92ZXJyaWRlIHRoZSBvdXQgb2Ygb3JkZXIgcG9saWN5IGZvciB0aGlzIHJ1bjogc3RyaWN0LCB3YXJuIG9yIGFsbG93IiwKCQkJVmFsaWRhdG9yOiBmdW5jKHBvbGljeSBzdHJpbmcpIGVycm9yIHsKCQkJCXJldHVybiBjb3JlLk91dE9mT3JkZXJQb2xpY3kocG9saWN5KS5WYWxpZGF0ZSgpCgkJCX0sCgkJfSwKCQkmY2xpLlN0cmluZ0ZsYWd7CgkJCU5hbWU6ICAibWV0cmljcy10ZXh0ZmlsZSIsCgkJCVVzYWdlOiAid3JpdGUgdGhlIG1ldHJpY3Mgb2YgdGhlIHJ1biB0byBhIGZpbGUsIGZvciB0aGUgdGV4dGZpbGUgY29sbGVjdG9yIG9mIHRoZSBub2RlIGV4cG9ydGVyIiwKCQl9LAoJfSwKCUFjdGlvbjogZnVuYyhjdHggY29udGV4dC5Db250ZXh0LCBjbWQgKmNsaS5Db21tYW5kKSBlcnJvciB7CgkJc3RlcHMsIGVyciA6PSBzdGVwc09mKGNtZCkKCQlpZiBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGVycgoJCX0KCQljb2xsZWN0b3IgOj0gbWV0cmljcy5OZXdDb2xsZWN0b3IoKQoJCW1pZ3JhdG9yLCBlcnIgOj0gY29ubmVjdE1pZ3JhdG9yKGN0eCwgZnVuYyhyYyAqY29yZS5Hb21pZ2VyQ29uZmlnKSB7CgkJCWlmIGNtZC5Jc1NldCgib3V0LW9mLW9yZGVyIikgewoJCQkJcmMuT3V0T2ZPcmRlciA9IGNvcmUuT3V0T2ZPcmRlclBvbGljeShjbWQuU3RyaW5nKCJvdXQtb2Ytb3JkZXIiKSkKCQkJfQoJCQlpZiBjbWQuSXNTZXQoIm1ldHJpY3MtdGV4dGZpbGUiKSB7CgkJCQlyYy5PYnNlcnZlcnMgPSBhcHBlbmQocmMuT2JzZXJ2ZXJzLCBjb2xsZWN0b3IpCgkJCX0KCQl9KQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZXJyCgkJfQoJCWlmIGNtZC5Cb29sKCJkcnktcnVuIikgewoJCQlyZXR1cm4gcHJpbnRQbGFuKGN0eCwgbWlncmF0b3IsIGNvcmUuRGlyZWN0aW9uVXAsIGNtZC5BcmdzKCkuR2V0KDApLCBzdGVwcykKCQl9CgkJaWYgc3RlcHMgPiAwIHsKCQkJZXJyID0gbWlncmF0b3IuVXBTdGVwcyhjdHgsIHN0ZXBzKQoJCX0gZWxzZSB7CgkJCWVyciA9IG1pZ3JhdG9yLlVwKGN0eCwgY21kLkFyZ3MoKS5HZXQoMCkpCgkJfQoJCWlmIGVyciAhPSBuaWwgewoJCQllcnIgPSBmbXQuRXJyb3JmKCJjYW5ub3QgbWlncmF0ZSB0aGUgZGF0YWJhc2U6ICV3IiwgZXJyKQoJCX0KCQlpZiBjbWQuSXNTZXQoIm1ldHJpY3MtdGV4dGZpbGUiKSB7CgkJCS8vIFRoZSBtZXRyaWNzIGFyZSB3cml0dGVuIGZvciB0aGUgZmFpbGVkIHJ1bnMgdG9vLgoJCQllcnIgPSBlcnJvcnMuSm9pbihlcnIsIGNvbGxlY3Rvci5SZWZyZXNoKGN0eCwgbWlncmF0b3IpLCBjb2xsZWN0b3IuV3JpdGVUZXh0ZmlsZShjbWQuU3RyaW5nKCJtZXRyaWNzLXRleHRmaWxlIikpKQoJCX0KCQlyZXR1cm4gZXJyCgl9LAp9Cgp2YXIgbWlncmF0ZURvd25DbWQgPSAmY2xpLkNvbW1hbmR7CglOYW1lOiAgICAiZG93biIsCglBbGlhc2VzOiBbXXN0cmluZ3siZCJ9LAoJVXNhZ2U6ICAgIm1pZ3JhdGUgdGhlIGRhdGFiYXNlIGRvd24gdG8gYSB2ZXJzaW9uIiwKCUZsYWdzOiBbXWNsaS5GbGFnewoJCWRyeVJ1bkZsYWcsCgkJc3RlcHNGbGFnLAoJCWFsbG93SXJyZXZlcnNpYmxlRmxhZywKCX0sCglBY3Rpb246IGZ1bmMoY3R4IGNvbnRleHQuQ29udGV4dCwgY21kICpjbGkuQ29tbWFuZCkgZXJyb3IgewoJCXN0ZXBzLCBlcnIgOj0gc3RlcHNPZihjbWQpCgkJaWYgZXJyICE9IG5pbCB7CgkJCXJldHVybiBlcnIKCQl9CgkJbWlncmF0b3IsIGVyciA6PSBjb25uZWN0TWlncmF0b3IoY3R4LCBhbGxvd0lycmV2ZXJzaWJsZShjbWQpKQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZXJyCgkJfQoJCWlmIGNtZC5Cb29sKCJkcnktcnVuIikgewoJCQlyZXR1cm4gcHJpbnRQbGFuKGN0eCwgbWlncmF0b3IsIGNvcmUuRGlyZWN0aW9uRG93biwgY21kLkFyZ3MoKS5HZXQoMCksIHN0ZXBzKQoJCX0KCQlpZiBzdGVwcyA+IDAgewoJCQllcnIgPSBtaWdyYXRvci5Eb3duU3RlcHMoY3R4LCBzdGVwcykKCQl9IGVsc2UgewoJCQllcnIgPSBtaWdyYXRvci5Eb3duKGN0eCwgY21kLkFyZ3MoKS5HZXQoMCkpCgkJfQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZm10LkVycm9yZigiY2Fubm90IG1pZ3JhdGUgdGhlIGRhdGFiYXNlOiAldyIsIGVycikKCQl9CgkJcmV0dXJuIG5pbAoJfSwKfQoKdmFyIHJlZG9DbWQgPSAmY2xpLkNvbW1hbmR7CglOYW1lOiAgInJlZG8iLAoJVXNhZ2U6ICJyZXZlcnQgdGhlIGxhc3QgbWlncmF0aW9uLCB0aGVuIGFwcGx5IGl0IGFnYWluIiwKCUZsYWdzOiBbXWNsaS5GbGFnewoJCWFsbG93SXJyZXZlcnNpYmxlRmxhZywKCX0sCglBY3Rpb246IGZ1bmMoY3R4IGNvbnRleHQuQ29udGV4dCwgY21kICpjbGkuQ29tbWFuZCkgZXJyb3IgewoJCW1pZ3JhdG9yLCBlcnIgOj0gY29ubmVjdE1pZ3JhdG9yKGN0eCwgYWxsb3dJcnJldmVyc2libGUoY21kKSkKCQlpZiBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGVycgoJCX0KCQlpZiBlcnIgOj0gbWlncmF0b3IuUmVkbyhjdHgpOyBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoImNhbm5vdCByZWRvIHRoZSBtaWdyYXRpb246ICV3IiwgZXJyKQoJCX0KCQlyZXR1cm4gbmlsCgl9LAp9Cgp2YXIgcmVzZXRDbWQgPSAmY2xpLkNvbW1hbmR7CglOYW1lOiAgInJlc2V0IiwKCVVzYWdlOiAicmV2ZXJ0IGFsbCB0aGUgbWlncmF0aW9ucyIsCglGbGFnczogW11jbGkuRmxhZ3sKCQkmY2xpLkJvb2xGbGFnewoJCQlOYW1lOiAgInllcyIsCgkJCVVzYWdlOiAiY29uZmlybSB0aGF0IGFsbCB0aGUgbWlncmF0aW9ucyBhcmUgcmV2ZXJ0ZWQiLAoJCX0sCgkJYWxsb3dJcnJldmVyc2libGVGbGFnLAoJfSwKCUFjdGlvbjogZnVuYyhjdHggY29udGV4dC5Db250ZXh0LCBjbWQgKmNsaS5Db21tYW5kKSBlcnJvciB7CgkJaWYgIWNtZC5Cb29sKCJ5ZXMiKSB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJyZXNldCByZXZlcnRzIGFsbCB0aGUgbWlncmF0aW9ucywgY29uZmlybSBpdCB3aXRoIC0teWVzIikKCQl9CgkJbWlncmF0b3IsIGVyciA6PSBjb25uZWN0TWlncmF0b3IoY3R4LCBhbGxvd0lycmV2ZXJzaWJsZShjbWQpKQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZXJyCgkJfQoJCWlmIGVyciA6PSBtaWdyYXRvci5SZXNldChjdHgpOyBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoImNhbm5vdCByZXNldCB0aGUgZGF0YWJhc2U6ICV3IiwgZXJyKQoJCX0KCQlyZXR1cm4gbmlsCgl9LAp9Cgp2YXIgZ290b0NtZCA9ICZjbGkuQ29tbWFuZHsKCU5hbWU6ICAgICAgImdvdG8iLAoJVXNhZ2U6ICAgICAibWlncmF0ZSB0aGUgZGF0YWJhc2UgdXAgb3IgZG93biB0byBhIHZlcnNpb24iLAoJQXJnc1VzYWdlOiAiPHZlcnNpb24+IiwKCUZsYWdzOiBbXWNsaS5GbGFnewoJCWFsbG93SXJyZXZlcnNpYmxlRmxhZywKCX0sCglBY3Rpb246IGZ1bmMoY3R4IGNvbnRleHQuQ29udGV4dCwgY21kICpjbGkuQ29tbWFuZCkgZXJyb3IgewoJCW1pZ3JhdG9yLCBlcnIgOj0gY29ubmVjdE1pZ3JhdG9yKGN0eCwgYWxsb3dJcnJldmVyc2libGUoY21kKSkKCQlpZiBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGVycgoJCX0KCQlpZiBlcnIgOj0gbWlncmF0b3IuR290byhjdHgsIGNtZC5BcmdzKCkuR2V0KDApKTsgZXJyICE9IG5pbCB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgbWlncmF0ZSB0aGUgZGF0YWJhc2U6ICV3IiwgZXJyKQoJCX0KCQlyZXR1cm4gbmlsCgl9LAp9Cgp2YXIgYmFzZWxpbmVDbWQgPSAmY2xpLkNvbW1hbmR7CglOYW1lOiAgICAgICJiYXNlbGluZSIsCglVc2FnZTogICAgICJyZWNvcmQgdGhlIG1pZ3JhdGlvbnMgdW50aWwgYSB2ZXJzaW9uIGFzIGFwcGxpZWQsIHdpdGhvdXQgZXhlY3V0aW5nIHRoZW0iLAoJQXJnc1VzYWdlOiAiPHZlcnNpb24+IiwKCUFjdGlvbjogZnVuYyhjdHggY29udGV4dC5Db250ZXh0LCBjbWQgKmNsaS5Db21tYW5kKSBlcnJvciB7CgkJbWlncmF0b3IsIGVyciA6PSBjb25uZWN0TWlncmF0b3IoY3R4KQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZXJyCgkJfQoJCWlmIGVyciA6PSBtaWdyYXRvci5CYXNlbGluZShjdHgsIGNtZC5BcmdzKCkuR2V0KDApKTsgZXJyICE9IG5pbCB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgYmFzZWxpbmUgdGhlIGRhdGFiYXNlOiAldyIsIGVycikKCQl9CgkJcmV0dXJuIG5pbAoJfSwKfQoKLy8gcHJpbnRQbGFuIHByaW50cyB0aGUgbWlncmF0aW9ucyB0aGF0IGEgcnVuIHRvIHRoZSB0YXJnZXQsIG9yIG9mIGEgbnVtYmVyIG9mIHN0ZXBzLCB3b3VsZCBnbyB0aHJvdWdoLgpmdW5jIHByaW50UGxhbihjdHggY29udGV4dC5Db250ZXh0LCBtaWdyYXRvciBjb3JlLkdvbWlnZXIsIGRpcmVjdGlvbiBjb3JlLkRpcmVjdGlvbiwgdGFyZ2V0IHN0cmluZywgc3RlcHMgaW50KSBlcnJvciB7Cgl2YXIgcGxhbiAqY29yZS5QbGFuCgl2YXIgZXJyIGVycm9yCglpZiBzdGVwcyA+IDAgewoJCXBsYW4sIGVyciA9IG1pZ3JhdG9yLlBsYW5TdGVwcyhjdHgsIGRpcmVjdGlvbiwgc3RlcHMpCgl9IGVsc2UgewoJCXBsYW4sIGVyciA9IG1pZ3JhdG9yLlBsYW4oY3R4LCBkaXJlY3Rpb24sIHRhcmdldCkKCX0KCWlmIGVyciAhPSBuaWwgewoJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgcGxhbiB0aGUgbWlncmF0aW9uOiAldyIsIGVycikKCX0KCWlmIGxlbihwbGFuLlJ1bnMoKSkgPT0gMCB7CgkJZm10LlByaW50bG4oIk5vdGhpbmcgdG8gbWlncmF0ZSIpCgl9Cgl3IDo9IHRhYndyaXRlci5OZXdXcml0ZXIob3MuU3Rkb3V0LCAwLCAwLCAyLCAnICcsIDApCglmbXQuRnByaW50bG4odywgIkFDVElPTlx0VkVSU0lPTlx0UkVBU09OIikKCWZvciBfLCBzdGVwIDo9IHJhbmdlIHBsYW4uU3RlcHMgewoJCWZtdC5GcHJpbnRmKHcsICIlc1x0JXNcdCVzXG4iLCBzdGVwLkFjdGlvbiwgc3RlcC5WZXJzaW9uLCBzdGVwLlJlYXNvbikKCX0KCWlmIGVyciA6PSB3LkZsdXNoKCk7IGVyciAhPSBuaWwgewoJCXJldHVybiBlcnIKCX0KCWlmIGxlbihwbGFuLk91dE9mT3JkZXIpID4gMCB7CgkJZm10LlByaW50ZigiT3V0IG9mIG9yZGVyOiAlcyBzb3J0IGJlZm9yZSB0aGUgbGF0ZXN0IGFwcGxpZWQgdmVyc2lvbiAlc1xuIiwgc3RyaW5ncy5Kb2luKHBsYW4uT3V0T2ZPcmRlciwgIiwgIiksIHBsYW4uTGF0ZXN0QXBwbGllZCkKCX0KCXJldHVybiBuaWwKfQoKdmFyIGdldE1pZ3JhdGlvblN0YXR1c0NtZCA9ICZjbGkuQ29tbWFuZHsKCU5hbWU6ICAgICJzdGF0dXMiLAoJQWxpYXNlczogW11zdHJpbmd7InMifSwKCVVzYWdlOiAgICJsaXN0IHRoZSBzdGF0dXMgb2YgYWxsIG1pZ3JhdGlvbnMiLAoJRmxhZ3M6IFtdY2xpLkZsYWd7CgkJJmNsaS5TdHJpbmdGbGFnewoJCQlOYW1lOiAgICAib3V0cHV0IiwKCQkJQWxpYXNlczogW11zdHJpbmd7Im8ifSwKCQkJVmFsdWU6ICAgInRhYmxlIiwKCQkJVXNhZ2U6ICAgIm91dHB1dCBmb3JtYXQ6IHRhYmxlIG9yIGpzb24iLAoJCX0sCgl9LAoJQWN0aW9uOiBmdW5jKGN0eCBjb250ZXh0LkNvbnRleHQsIGNtZCAqY2xpLkNvbW1hbmQpIGVycm9yIHsKCQltaWdyYXRvciwgZXJyIDo9IGNvbm5lY3RNaWdyYXRvcihjdHgpCgkJaWYgZXJyICE9IG5pbCB7CgkJCXJldHVybiBlcnIKCQl9CgkJc3RhdHVzZXMsIGVyciA6PSBtaWdyYXRvci5TdGF0dXMoY3R4KQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZm10LkVycm9yZigiY2Fubm90IGdldCB0aGUgbWlncmF0aW9uIHN0YXR1czogJXciLCBlcnIpCgkJfQoJCXN3aXRjaCBjbWQuU3RyaW5nKCJvdXRwdXQiKSB7CgkJY2FzZSAianNvbiI6CgkJCWVuY29kZXIgOj0ganNvbi5OZXdFbmNvZGVyKG9zLlN0ZG91dCkKCQkJZW5jb2Rlci5TZXRJbmRlbnQoIiIsICIgICIpCgkJCXJldHVybiBlbmNvZGVyLkVuY29kZShzdGF0dXNlcykKCQljYXNlICJ0YWJsZSI6CgkJCXJldHVybiBwcmludFN0YXR1c1RhYmxlKHN0YXR1c2VzKQoJCWRlZmF1bHQ6CgkJCXJldHVybiBmbXQuRXJyb3JmKCJ1bmtub3duIG91dHB1dCBmb3JtYXQ6ICVzIiwgY21kLlN0cmluZygib3V0cHV0IikpCgkJfQoJfSwKfQoKLy8gcHJpbnRTdGF0dXNUYWJsZSBwcmludHMgdGhlIG1pZ3JhdGlvbiBzdGF0dXNlcyBhcyBhIHRhYmxlLgpmdW5jIHByaW50U3RhdHVzVGFibGUoc3RhdHVzZXMgW11jb3JlLk1pZ3JhdGlvblN0YXR1cykgZXJyb3IgewoJdyA6PSB0YWJ3cml0ZXIuTmV3V3JpdGVyKG9zLlN0ZG91dCwgMCwgMCwgMiwgJyAnLCAwKQoJZm10LkZwcmludGxuKHcsICJWRVJTSU9OXHRTVEFURVx0QVBQTElFRCBBVFx0RFVSQVRJT04iKQoJZm9yIF8sIHN0YXR1cyA6PSByYW5nZSBzdGF0dXNlcyB7CgkJYXBwbGllZEF0LCBkdXJhdGlvbiA6PSAiLSIsICItIgoJCWlmIHN0YXR1cy5BcHBsaWVkQXQgIT0gbmlsIHsKCQkJYXBwbGllZEF0ID0gc3RhdHVzLkFwcGxpZWRBdC5Gb3JtYXQodGltZS5SRkMzMzM5KQoJCX0KCQlpZiBzdGF0dXMuRHVyYXRpb24gPiAwIHsKCQkJZHVyYXRpb24gPSBzdGF0dXMuRHVyYXRpb24uU3RyaW5nKCkKCQl9CgkJc3RhdGUgOj0gc3RyaW5nKHN0YXR1cy5TdGF0ZSkKCQlpZiBzdGF0dXMuQmFzZWxpbmVkIHsKCQkJc3RhdGUgKz0gIiAoYmFzZWxpbmUpIgoJCX0KCQlmbXQuRnByaW50Zih3LCAiJXNcdCVzXHQlc1x0JXNcbiIsIHN0YXR1cy5WZXJzaW9uLCBzdGF0ZSwgYXBwbGllZEF0LCBkdXJhdGlvbikKCX0KCXJldHVybiB3LkZsdXNoKCkKfQoKdmFyIGZvcmNlQ21kID0gJmNsaS5Db21tYW5kewoJTmFtZTogICAgICAiZm9yY2UiLAoJVXNhZ2U6ICAgICAic2V0IHRoZSBzdGF0dXMgb2YgYSB2ZXJzaW9uIHdpdGhvdXQgZXhlY3V0aW5nIGl0cyBtaWdyYXRpb24iLAoJQXJnc1VzYWdlOiAiPHZlcnNpb24+IiwKCUZsYWdzOiBbXWNsaS5GbGFnewoJCSZjbGkuU3RyaW5nRmxhZ3sKCQkJTmFtZTogICAgICJzdGF0dXMiLAoJCQlVc2FnZTogICAgInRoZSBzdGF0dXMgdG8gc2V0OiBhcHBsaWVkIG9yIHBlbmRpbmciLAoJCQlSZXF1aXJlZDogdHJ1ZSwKCQl9LAoJfSwKCUFjdGlvbjogZnVuYyhjdHggY29udGV4dC5Db250ZXh0LCBjbWQgKmNsaS5Db21tYW5kKSBlcnJvciB7CgkJbWlncmF0b3IsIGVyciA6PSBjb25uZWN0TWlncmF0b3IoY3R4KQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZXJyCgkJfQoJCWlmIGVyciA6PSBtaWdyYXRvci5Gb3JjZShjdHgsIGNtZC5BcmdzKCkuR2V0KDApLCBjb3JlLk1pZ3JhdGlvblN0YXRlKGNtZC5TdHJpbmcoInN0YXR1cyIpKSk7IGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZm10LkVycm9yZigiY2Fubm90IGZvcmNlIHRoZSB2ZXJzaW9uOiAldyIsIGVycikKCQl9CgkJcmV0dXJuIG5pbAoJfSwKfQoKdmFyIHJldHJ5Q21kID0gJmNsaS5Db21tYW5kewoJTmFtZTogICAgICAicmV0cnkiLAoJVXNhZ2U6ICAgICAiYXBwbHkgYSBkaXJ0eSBvciBpbiBwcm9ncmVzcyBtaWdyYXRpb24gYWdhaW4iLAoJQXJnc1VzYWdlOiAiPHZlcnNpb24+IiwKCUFjdGlvbjogZnVuYyhjdHggY29udGV4dC5Db250ZXh0LCBjbWQgKmNsaS5Db21tYW5kKSBlcnJvciB7CgkJbWlncmF0b3IsIGVyciA6PSBjb25uZWN0TWlncmF0b3IoY3R4KQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZXJyCgkJfQoJCWlmIGVyciA6PSBtaWdyYXRvci5SZXRyeShjdHgsIGNtZC5BcmdzKCkuR2V0KDApKTsgZXJyICE9IG5pbCB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgcmV0cnkgdGhlIG1pZ3JhdGlvbjogJXciLCBlcnIpCgkJfQoJCXJldHVybiBuaWwKCX0sCn0KCnZhciByZXBhaXJDbWQgPSAmY2xpLkNvbW1hbmR7CglOYW1lOiAgInJlcGFpciIsCglVc2FnZTogImxpc3QgdGhlIGRpcnR5IGFuZCBpbiBwcm9ncmVzcyBtaWdyYXRpb25zIHdoaWNoIG5lZWQgYSByZWNvdmVyeSIsCglGbGFnczogW11jbGkuRmxhZ3sKCQkmY2xpLkR1cmF0aW9uRmxhZ3sKCQkJTmFtZTogICJvbGRlci10aGFuIiwKCQkJVXNhZ2U6ICJvbmx5IGxpc3QgdGhlIG1pZ3JhdGlvbnMgd2hpY2ggc3RhcnRlZCBiZWZvcmUgdGhpcyBkdXJhdGlvbiIsCgkJfSwKCX0sCglBY3Rpb246IGZ1bmMoY3R4IGNvbnRleHQuQ29udGV4dCwgY21kICpjbGkuQ29tbWFuZCkgZXJyb3IgewoJCW1pZ3JhdG9yLCBlcnIgOj0gY29ubmVjdE1pZ3JhdG9yKGN0eCkKCQlpZiBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGVycgoJCX0KCQlzY2hlbWFzLCBlcnIgOj0gbWlncmF0b3IuUmVwYWlyKGN0eCwgY21kLkR1cmF0aW9uKCJvbGRlci10aGFuIikpCgkJaWYgZXJyICE9IG5pbCB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgbGlzdCB0aGUgbWlncmF0aW9ucyB0byByZXBhaXI6ICV3IiwgZXJyKQoJCX0KCQlpZiBsZW4oc2NoZW1hcykgPT0gMCB7CgkJCWZtdC5QcmludGxuKCJOb3RoaW5nIHRvIHJlcGFpciIpCgkJCXJldHVybiBuaWwKCQl9CgkJdyA6PSB0YWJ3cml0ZXIuTmV3V3JpdGVyKG9zLlN0ZG91dCwgMCwgMCwgMiwgJyAnLCAwKQoJCWZtdC5GcHJpbnRsbih3LCAiVkVSU0lPTlx0U1RBVFVTXHRUSU1FU1RBTVAiKQoJCWZvciBfLCBzY2hlbWEgOj0gcmFuZ2Ugc2NoZW1hcyB7CgkJCWZtdC5GcHJpbnRmKHcsICIlc1x0JXNcdCVzXG4iLCBzY2hlbWEuVmVyc2lvbiwgc2NoZW1hLlN0YXR1cywgc2NoZW1hLlRpbWVzdGFtcC5Gb3JtYXQodGltZS5SRkMzMzM5KSkKCQl9CgkJaWYgZXJyIDo9IHcuRmx1c2goKTsgZXJyICE9IG5pbCB7CgkJCXJldHVybiBlcnIKCQl9CgkJZm10LlByaW50bG4oIlJlY292ZXIgdGhlbSB3aXRoICdyZXRyeSA8dmVyc2lvbj4nIG9yICdmb3JjZSA8dmVyc2lvbj4gLS1zdGF0dXMgYXBwbGllZHxwZW5kaW5nJyIpCgkJcmV0dXJuIG5pbAoJfSwKfQoKdmFyIHZhbGlkYXRlQ21kID0gJmNsaS5Db21tYW5kewoJTmFtZTogICJ2YWxpZGF0ZSIsCglVc2FnZTogImNoZWNrIHRoYXQgdGhlIGFwcGxpZWQgbWlncmF0aW9ucyBoYXZlIG5vdCBiZWVuIG1vZGlmaWVkIHNpbmNlIHRoZXkgd2VyZSBhcHBsaWVkIiwKCUFjdGlvbjogZnVuYyhjdHggY29udGV4dC5Db250ZXh0LCBfICpjbGkuQ29tbWFuZCkgZXJyb3IgewoJCW1pZ3JhdG9yLCBlcnIgOj0gY29ubmVjdE1pZ3JhdG9yKGN0eCkKCQlpZiBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGVycgoJCX0KCQltaXNtYXRjaGVzLCBlcnIgOj0gbWlncmF0b3IuVmFsaWRhdGUoY3R4KQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZm10LkVycm9yZigiY2Fubm90IHZhbGlkYXRlIHRoZSBtaWdyYXRpb25zOiAldyIsIGVycikKCQl9CgkJaWYgbGVuKG1pc21hdGNoZXMpID09IDAgewoJCQlmbXQuUHJpbnRsbigiQWxsIGFwcGxpZWQgbWlncmF0aW9ucyBtYXRjaCB0aGVpciBjaGVja3N1bSIpCgkJCXJldHVybiBuaWwKCQl9CgkJdyA6PSB0YWJ3cml0ZXIuTmV3V3JpdGVyKG9zLlN0ZG91dCwgMCwgMCwgMiwgJyAnLCAwKQoJCWZtdC5GcHJpbnRsbih3LCAiVkVSU0lPTlx0UkVDT1JERURcdENVUlJFTlQiKQoJCWZvciBfLCBtaXNtYXRjaCA6PSByYW5nZSBtaXNtYXRjaGVzIHsKCQkJZm10LkZwcmludGYodywgIiVzXHQlc1x0JXNcbiIsIG1pc21hdGNoLlZlcnNpb24sIG1pc21hdGNoLlJlY29yZGVkLCBtaXNtYXRjaC5DdXJyZW50KQoJCX0KCQlpZiBlcnIgOj0gdy5GbHVzaCgpOyBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGVycgoJCX0KCQlyZXR1cm4gZm10LkVycm9yZigiJWQgYXBwbGllZCBtaWdyYXRpb24ocykgaGF2ZSBiZWVuIG1vZGlmaWVkIHNpbmNlIHRoZXkgd2VyZSBhcHBsaWVkIiwgbGVuKG1pc21hdGNoZXMpKQoJfSwKfQoKdmFyIGxvY2tDbWQgPSAmY2xpLkNvbW1hbmR7CglOYW1lOiAgImxvY2siLAoJVXNhZ2U6ICJpbnNwZWN0IHRoZSBtaWdyYXRpb24gbG9jayIsCglDb21tYW5kczogW10qY2xpLkNvbW1hbmR7CgkJewoJCQlOYW1lOiAgInN0YXR1cyIsCgkJCVVzYWdlOiAiZ2V0IHRoZSBjdXJyZW50IGhvbGRlciBvZiB0aGUgbWlncmF0aW9uIGxvY2siLAoJCQlBY3Rpb246IGZ1bmMoY3R4IGNvbnRleHQuQ29udGV4dCwgXyAqY2xpLkNvbW1hbmQpIGVycm9yIHsKCQkJCW1pZ3JhdG9yLCBlcnIgOj0gY29ubmVjdE1pZ3JhdG9yKGN0eCkKCQkJCWlmIGVyciAhPSBuaWwgewoJCQkJCXJldHVybiBlcnIKCQkJCX0KCQkJCWxvY2ssIGVyciA6PSBtaWdyYXRvci5Mb2NrU3RhdHVzKGN0eCkKCQkJCWlmIGVyciAhPSBuaWwgewoJCQkJCXJldHVybiBmbXQuRXJyb3JmKCJjYW5ub3QgZ2V0IHRoZSBtaWdyYXRpb24gbG9jazogJXciLCBlcnIpCgkJCQl9CgkJCQlpZiBsb2NrID09IG5pbCB7CgkJCQkJZm10LlByaW50bG4oIlRoZSBtaWdyYXRpb24gbG9jayBpcyBmcmVlIikKCQkJCQlyZXR1cm4gbmlsCgkJCQl9CgkJCQlzdGF0ZSA6PSAiaGVsZCIKCQkJCWlmIGxvY2suSXNFeHBpcmVkKCkgewoJCQkJCXN0YXRlID0gImV4cGlyZWQiCgkJCQl9CgkJCQlmbXQuUHJpbnRmKCJPd25lcjogJXMsIEFjcXVpcmVkIGF0OiAlcywgRXhwaXJlcyBhdDogJXMgKCVzKVxuIiwKCQkJCQlsb2NrLk93bmVyLCBsb2NrLkFjcXVpcmVkQXQuRm9ybWF0KHRpbWUuUkZDMzMzOSksIGxvY2suRXhwaXJlc0F0LkZvcm1hdCh0aW1lLlJGQzMzMzkpLCBzdGF0ZSkKCQkJCXJldHVybiBuaWwKCQkJfSwKCQl9LAoJfSwKfQoKdmFyIHVubG9ja0NtZCA9ICZjbGkuQ29tbWFuZHsKCU5hbWU6ICAidW5sb2NrIiwKCVVzYWdlOiAicmVsZWFzZSB0aGUgbWlncmF0aW9uIGxvY2sgaGVsZCBieSBhIGNyYXNoZWQgbWlncmF0b3IiLAoJRmxhZ3M6IFtdY2xpLkZsYWd7CgkJJmNsaS5Cb29sRmxhZ3sKCQkJTmFtZTogICJmb3JjZSIsCgkJCVVzYWdlOiAicmVsZWFzZSB0aGUgbG9jayByZWdhcmRsZXNzIG9mIGl0cyBvd25lciIsCgkJfSwKCX0sCglBY3Rpb246IGZ1bmMoY3R4IGNvbnRleHQuQ29udGV4dCwgY21kICpjbGkuQ29tbWFuZCkgZXJyb3IgewoJCWlmICFjbWQuQm9vbCgiZm9yY2UiKSB7CgkJCXJldHVybiBmbXQuRXJyb3JmKCJ0aGUgbG9jayBtYXkgYmUgaGVsZCBieSBhIHJ1bm5pbmcgbWlncmF0b3IsIHVzZSAtLWZvcmNlIHRvIHJlbGVhc2UgaXQgYW55d2F5IikKCQl9CgkJbWlncmF0b3IsIGVyciA6PSBjb25uZWN0TWlncmF0b3IoY3R4KQoJCWlmIGVyciAhPSBuaWwgewoJCQlyZXR1cm4gZXJyCgkJfQoJCWlmIGVyciA6PSBtaWdyYXRvci5Gb3JjZVVubG9jayhjdHgpOyBlcnIgIT0gbmlsIHsKCQkJcmV0dXJuIGZtdC5FcnJvcmYoImNhbm5vdCByZWxlYXNlIHRoZSBtaWdyYXRpb24gbG9jazogJXciLCBlcnIpCgkJfQoJCXJldHVybiBuaWwKCX0sCn0KCi8vIGNvbm5lY3RNaWdyYXRvciBsb2FkcyB0aGUgZ29taWdlci5yYyBmaWxlLCB0aGVuIGNyZWF0ZXMgYW5kIGNvbm5lY3RzIHRoZSBtaWdyYXRvci4KLy8gVGhlIG9wdGlvbnMgb3ZlcnJpZGUgdGhlIGdvbWlnZXIucmMgZmlsZSBmb3IgYSBzaW5nbGUgcnVuLgpmdW5jIGNvbm5lY3RNaWdyYXRvcihjdHggY29udGV4dC5Db250ZXh0LCBvcHRpb25zIC4uLmZ1bmMocmMgKmNvcmUuR29taWdlckNvbmZpZykpIChjb3JlLkdvbWlnZXIsIGVycm9yKSB7CglyYywgZXJyIDo9IGNvcmUuR2V0R29taWdlclJDKHJjUGF0aCkKCWlmIGVyciAhPSBuaWwgewoJCXJldHVybiBuaWwsIGZtdC5FcnJvcmYoImNhbm5vdCBsb2FkIHRoZSBnb21pZ2VyLnJjIGZpbGU6ICV3IiwgZXJyKQoJfQoJaWYgIWdlbmVyYXRvci5Jc1NyY0NvZGVJbml0aWFsaXplZChyYykgewoJCXJldHVybiBuaWwsIGZtdC5FcnJvcmYoInRoZSBzb3VyY2UgY29kZSBpcyBOT1QgSU5JVElBTElaRUQiKQoJfQoJZm9yIF8sIG9wdGlvbiA6PSByYW5nZSBvcHRpb25zIHsKCQlvcHRpb24ocmMpCgl9CgltaWdyYXRvciA6PSBOZXdNaWdyYXRvcihyYykKCWlmIGVyciA6PSBtaWdyYXRvci5Db25uZWN0KGN0eCk7IGVyciAhPSBuaWwgewoJCXJldHVybiBuaWwsIGZtdC5FcnJvcmYoImNhbm5vdCBjb25uZWN0IHRvIGRhdGFiYXNlOiAldyIsIGVycikKCX0KCXJldHVybiBtaWdyYXRvciwgbmlsCn0K`
@@ -3,8 +3,8 @@
 // and generating timestamped migration files.
 //
 // The package handles three main template types:
-// - Migration script template - For individual migration files
-// - Migrator template - For the migration executor
+// - Migration script template - For individual migration files, with an irreversible variant
+// - Migrator template - For the migration executor, with a variant per database plugin
 // - CLI template - For command line interface
 //
 // Templates are stored as base64 encoded strings and decoded at runtime.
@@ -13,6 +13,7 @@
 // - Initialize source code structure
 // - Generate new migration files with timestamps
 // - Check initialization status
+// - Register the migrations with their checksums in the registry.mg.go file
 //
 // Usage requires a GomigerConfig that specifies:
 // - Package name for generated files
@@ -28,8 +29,11 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/ParteeLabs/gomiger/core"
@@ -42,6 +46,30 @@ type Template struct {
 	node *ast.File
 }
 
+// pluginMigratorTemplates are the migrator templates of the database plugins, by GomigerConfig.Plugin.
+var pluginMigratorTemplates = map[string]string{
+	"sqlite":   SqliteMigratorTemplateBase64,
+	"postgres": PostgresMigratorTemplateBase64,
+	"mysql":    MysqlMigratorTemplateBase64,
+}
+
+// parseTemplate decodes & parses a preset template string.
+func parseTemplate(encoded string) (Template, error) {
+	content, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return Template{}, fmt.Errorf("failed to decode template content: %w", err)
+	}
+	fs := token.NewFileSet()
+	node, err := parser.ParseFile(fs, "", content, parser.ParseComments)
+	if err != nil {
+		return Template{}, fmt.Errorf("failed to parse template content to ast.File node: %w", err)
+	}
+	return Template{
+		fs,
+		node,
+	}, nil
+}
+
 // LoadTemplates load the preset template strings to ast.Node
 func LoadTemplates() ([]Template, error) {
 	encodedTemplates := []string{
@@ -52,30 +80,38 @@ func LoadTemplates() ([]Template, error) {
 	templates := make([]Template, 0)
 
 	for _, encoded := range encodedTemplates {
-		content, decodeErr := base64.StdEncoding.DecodeString(encoded)
-		if decodeErr != nil {
-			return nil, fmt.Errorf("failed to decode template content: %w", decodeErr)
-		}
-		fs := token.NewFileSet()
-		node, err := parser.ParseFile(fs, "", content, parser.ParseComments)
+		template, err := parseTemplate(encoded)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse template content to ast.File node: %w", err)
+			return nil, err
 		}
-		templates = append(templates, Template{
-			fs,
-			node,
-		})
+		templates = append(templates, template)
 	}
 	return templates, nil
 }
 
+// loadMigratorTemplate loads the migrator template of a database plugin, the plugin agnostic one if it is empty.
+func loadMigratorTemplate(plugin string) (Template, error) {
+	if plugin == "" {
+		return parseTemplate(MigratorTemplateBase64)
+	}
+	encoded, ok := pluginMigratorTemplates[plugin]
+	if !ok {
+		plugins := slices.Sorted(maps.Keys(pluginMigratorTemplates))
+		return Template{}, fmt.Errorf("unknown plugin %s, only %s are supported", plugin, strings.Join(plugins, ", "))
+	}
+	return parseTemplate(encoded)
+}
+
 // InitSrcCode initializes the source code
 func InitSrcCode(rc *core.GomigerConfig) error {
 	templates, err := LoadTemplates()
 	if err != nil {
 		return fmt.Errorf("cannot load the templates: %w", err)
 	}
-	migrator := templates[1]
+	migrator, err := loadMigratorTemplate(rc.Plugin)
+	if err != nil {
+		return fmt.Errorf("cannot load the migrator template: %w", err)
+	}
 	cli := templates[2]
 	helper.UpdatePackageName(migrator.node, rc.PkgName)
 	helper.UpdatePackageName(cli.node, rc.PkgName)
@@ -92,6 +128,10 @@ func InitSrcCode(rc *core.GomigerConfig) error {
 	if err := helper.ExportFile(cli.node, cli.fs, rc.Path+"/cli.mg.go"); err != nil {
 		return fmt.Errorf("cannot init the cli file: %w", err)
 	}
+	/// init the registry file
+	if err := GenRegistryFile(rc); err != nil {
+		return fmt.Errorf("cannot init the registry file: %w", err)
+	}
 	return nil
 }
 
@@ -103,12 +143,20 @@ func IsSrcCodeInitialized(rc *core.GomigerConfig) bool {
 
 // GenMigrationFile generates a migration file
 func GenMigrationFile(rc *core.GomigerConfig, name string) error {
+	return genMigrationFile(rc, name, MigrationScriptTemplateBase64)
+}
+
+// GenIrreversibleMigrationFile generates the file of a migration which cannot be undone, without down method.
+func GenIrreversibleMigrationFile(rc *core.GomigerConfig, name string) error {
+	return genMigrationFile(rc, name, IrreversibleMigrationScriptTemplateBase64)
+}
+
+func genMigrationFile(rc *core.GomigerConfig, name string, encoded string) error {
 	// Create the migration file path
 	timestamp := time.Now().Format("200601021504")
 	filePath := filepath.Join(rc.Path, fmt.Sprintf("%s_%s.mg.go", timestamp, name))
 
-	templates, err := LoadTemplates()
-	migration := templates[0]
+	migration, err := parseTemplate(encoded)
 	if err != nil {
 		return fmt.Errorf("cannot load the templates: %w", err)
 	}
@@ -123,5 +171,9 @@ func GenMigrationFile(rc *core.GomigerConfig, name string) error {
 	if err != nil {
 		return fmt.Errorf("cannot generate the migration file: %w", err)
 	}
+	/// register the new migration
+	if err := GenRegistryFile(rc); err != nil {
+		return fmt.Errorf("cannot update the registry file: %w", err)
+	}
 	return nil
 }
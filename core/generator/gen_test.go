@@ -123,6 +123,54 @@ func TestInitSrcCode(t *testing.T) {
 			t.Error("Expected error for invalid path")
 		}
 	})
+
+	for plugin, constructor := range map[string]string{
+		"sqlite":   "sqliteminger.NewSqliteminger(config)",
+		"postgres": "pgminger.NewPgminger(config)",
+		"mysql":    "mysqlminger.NewMysqlminger(config)",
+	} {
+		t.Run("scaffolds the migrator of the "+plugin+" plugin", func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			rc := &core.GomigerConfig{
+				Path:    tmpDir,
+				PkgName: "test",
+				Plugin:  plugin,
+			}
+
+			if err := InitSrcCode(rc); err != nil {
+				t.Fatalf("InitSrcCode with plugin failed: %v", err)
+			}
+
+			migratorContent, err := os.ReadFile(filepath.Join(tmpDir, "migrator.mg.go"))
+			if err != nil {
+				t.Fatalf("Failed to read migrator file: %v", err)
+			}
+			for _, expected := range []string{"package test", constructor} {
+				if !strings.Contains(string(migratorContent), expected) {
+					t.Errorf("migrator.mg.go does not contain %q", expected)
+				}
+			}
+		})
+	}
+
+	t.Run("returns error for an unknown plugin", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		rc := &core.GomigerConfig{
+			Path:    tmpDir,
+			PkgName: "test",
+			Plugin:  "oracle",
+		}
+
+		err := InitSrcCode(rc)
+		if err == nil || !strings.Contains(err.Error(), "unknown plugin oracle, only mysql, postgres, sqlite are supported") {
+			t.Errorf("Expected error for the unknown plugin, got: %v", err)
+		}
+		if IsSrcCodeInitialized(rc) {
+			t.Error("Expected no migrator file for an unknown plugin")
+		}
+	})
 }
 
 func TestIsSrcCodeInitialized(t *testing.T) {
@@ -308,6 +356,46 @@ func TestGenMigrationFile(t *testing.T) {
 	})
 }
 
+func TestGenIrreversibleMigrationFile(t *testing.T) {
+	t.Run("creates migration file without down method", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		rc := &core.GomigerConfig{
+			Path:    tmpDir,
+			PkgName: "migrations",
+		}
+
+		if err := GenIrreversibleMigrationFile(rc, "drop_legacy_column"); err != nil {
+			t.Fatalf("GenIrreversibleMigrationFile failed: %v", err)
+		}
+
+		files, err := filepath.Glob(filepath.Join(tmpDir, "*_drop_legacy_column.mg.go"))
+		if err != nil || len(files) != 1 {
+			t.Fatalf("Expected 1 migration file, got: %v", files)
+		}
+		content, err := os.ReadFile(files[0])
+		if err != nil {
+			t.Fatalf("Failed to read generated file: %v", err)
+		}
+		contentStr := string(content)
+		if !strings.Contains(contentStr, "_drop_legacy_column_Up(") {
+			t.Error("Generated file doesn't contain Up function")
+		}
+		if strings.Contains(contentStr, "_drop_legacy_column_Down(") {
+			t.Error("Generated file contains a Down function")
+		}
+
+		// The registry registers the migration without Down.
+		registry, err := os.ReadFile(filepath.Join(tmpDir, RegistryFileName))
+		if err != nil {
+			t.Fatalf("Failed to read the registry file: %v", err)
+		}
+		if !strings.Contains(string(registry), "_drop_legacy_column_Up,") || strings.Contains(string(registry), "Down:") {
+			t.Errorf("Unexpected registry file:\n%s", registry)
+		}
+	})
+}
+
 func TestIntegration_FullWorkflow(t *testing.T) {
 	t.Run("complete initialization and generation workflow", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -349,22 +437,24 @@ func TestIntegration_FullWorkflow(t *testing.T) {
 			t.Fatalf("Failed to read directory: %v", err)
 		}
 
-		// Should have: migrator.mg.go, cli.mg.go, and 2 generated migrations
-		if len(entries) != 4 {
-			t.Errorf("Expected 4 files, got: %d", len(entries))
+		// Should have: migrator.mg.go, cli.mg.go, registry.mg.go and 2 generated migrations
+		if len(entries) != 5 {
+			t.Errorf("Expected 5 files, got: %d", len(entries))
 			for _, entry := range entries {
 				t.Logf("  - %s", entry.Name())
 			}
 		}
 
 		// Verify file types
-		var hasMigrator, hasCli, migrationCount int
+		var hasMigrator, hasCli, hasRegistry, migrationCount int
 		for _, entry := range entries {
 			name := entry.Name()
 			if name == "migrator.mg.go" {
 				hasMigrator++
 			} else if name == "cli.mg.go" {
 				hasCli++
+			} else if name == RegistryFileName {
+				hasRegistry++
 			} else if strings.HasSuffix(name, ".mg.go") {
 				migrationCount++
 			}
@@ -376,6 +466,9 @@ func TestIntegration_FullWorkflow(t *testing.T) {
 		if hasCli != 1 {
 			t.Errorf("Expected 1 cli.mg.go, found %d", hasCli)
 		}
+		if hasRegistry != 1 {
+			t.Errorf("Expected 1 %s, found %d", RegistryFileName, hasRegistry)
+		}
 		if migrationCount != 2 {
 			t.Errorf("Expected 2 migration files, found %d", migrationCount)
 		}
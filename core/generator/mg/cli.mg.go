@@ -7,12 +7,18 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/ParteeLabs/gomiger/core"
 	"github.com/ParteeLabs/gomiger/core/generator"
+	"github.com/ParteeLabs/gomiger/core/metrics"
 	"github.com/urfave/cli/v3"
 )
 
@@ -29,16 +35,65 @@ func Run() {
 				Usage:       "Path to the gomiger.rc file",
 				Destination: &rcPath,
 			},
+			&cli.StringFlag{
+				Name:     "log-format",
+				Category: "global",
+				Value:    "text",
+				Usage:    "Format of the logs: text or json",
+			},
+			&cli.StringFlag{
+				Name:     "log-level",
+				Category: "global",
+				Value:    "info",
+				Usage:    "Minimum level of the logs: debug, info, warn or error",
+			},
+		},
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			logger, err := newLogger(cmd.String("log-format"), cmd.String("log-level"))
+			if err != nil {
+				return ctx, err
+			}
+			slog.SetDefault(logger)
+			return ctx, nil
 		},
 		Commands: []*cli.Command{
 			newCmd,
+			generateCmd,
 			migrateUpCmd,
 			migrateDownCmd,
+			redoCmd,
+			resetCmd,
+			gotoCmd,
+			baselineCmd,
 			getMigrationStatusCmd,
+			forceCmd,
+			retryCmd,
+			repairCmd,
+			validateCmd,
+			lockCmd,
+			unlockCmd,
 		},
 	}
 	if err := cmd.Run(context.Background(), os.Args); err != nil {
-		log.Fatal(err)
+		slog.Error("gomiger failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// newLogger creates the logger of the migrator, writing to stderr.
+func newLogger(format string, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %s, only debug, info, warn and error are allowed", level)
+	}
+	options := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, options)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %s, only text and json are allowed", format)
 	}
 }
 
@@ -46,6 +101,12 @@ var newCmd = &cli.Command{
 	Name:    "new",
 	Aliases: []string{"n"},
 	Usage:   "generate a new migration",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "irreversible",
+			Usage: "generate a migration which cannot be undone, without down method",
+		},
+	},
 	Action: func(_ context.Context, cmd *cli.Command) error {
 		rc, err := core.GetGomigerRC(rcPath)
 		if err != nil {
@@ -54,33 +115,123 @@ var newCmd = &cli.Command{
 		if !generator.IsSrcCodeInitialized(rc) {
 			return fmt.Errorf("the source code is NOT INITIALIZED")
 		}
-		if err := generator.GenMigrationFile(rc, cmd.Args().Get(0)); err != nil {
+		genMigrationFile := generator.GenMigrationFile
+		if cmd.Bool("irreversible") {
+			genMigrationFile = generator.GenIrreversibleMigrationFile
+		}
+		if err := genMigrationFile(rc, cmd.Args().Get(0)); err != nil {
 			return fmt.Errorf("cannot generate migration file: %w", err)
 		}
 		return nil
 	},
 }
 
+var generateCmd = &cli.Command{
+	Name:  "generate",
+	Usage: "register the migrations of the source code in the registry file",
+	Action: func(_ context.Context, _ *cli.Command) error {
+		rc, err := core.GetGomigerRC(rcPath)
+		if err != nil {
+			return fmt.Errorf("cannot load the gomiger.rc file: %w", err)
+		}
+		if !generator.IsSrcCodeInitialized(rc) {
+			return fmt.Errorf("the source code is NOT INITIALIZED")
+		}
+		if err := generator.GenRegistryFile(rc); err != nil {
+			return fmt.Errorf("cannot generate the registry file: %w", err)
+		}
+		return nil
+	},
+}
+
+var dryRunFlag = &cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "print the migrations that would be executed, without executing them",
+}
+
+var allowIrreversibleFlag = &cli.BoolFlag{
+	Name:  "allow-irreversible",
+	Usage: "cross the irreversible migrations: forget them without reverting their changes",
+}
+
+// allowIrreversible overrides the gomiger.rc file with the --allow-irreversible flag.
+func allowIrreversible(cmd *cli.Command) func(rc *core.GomigerConfig) {
+	return func(rc *core.GomigerConfig) {
+		rc.AllowIrreversible = cmd.Bool("allow-irreversible")
+	}
+}
+
+var stepsFlag = &cli.IntFlag{
+	Name:  "steps",
+	Usage: "migrate the next N migrations up, or the last N migrations down, instead of going to a version",
+}
+
+// stepsOf returns the number of steps of the run, zero when it goes to a version.
+func stepsOf(cmd *cli.Command) (int, error) {
+	if !cmd.IsSet("steps") {
+		return 0, nil
+	}
+	if cmd.Args().Present() {
+		return 0, fmt.Errorf("a version and --steps cannot be used together")
+	}
+	if cmd.Int("steps") <= 0 {
+		return 0, fmt.Errorf("--steps must be positive")
+	}
+	return cmd.Int("steps"), nil
+}
+
 var migrateUpCmd = &cli.Command{
 	Name:    "up",
 	Aliases: []string{"m"},
 	Usage:   "migrate the database up to a version",
+	Flags: []cli.Flag{
+		dryRunFlag,
+		stepsFlag,
+		&cli.StringFlag{
+			Name:  "out-of-order",
+			Usage: "override the out of order policy for this run: strict, warn or allow",
+			Validator: func(policy string) error {
+				return core.OutOfOrderPolicy(policy).Validate()
+			},
+		},
+		&cli.StringFlag{
+			Name:  "metrics-textfile",
+			Usage: "write the metrics of the run to a file, for the textfile collector of the node exporter",
+		},
+	},
 	Action: func(ctx context.Context, cmd *cli.Command) error {
-		rc, err := core.GetGomigerRC(rcPath)
+		steps, err := stepsOf(cmd)
 		if err != nil {
-			return fmt.Errorf("cannot load the gomiger.rc file: %w", err)
+			return err
 		}
-		if !generator.IsSrcCodeInitialized(rc) {
-			return fmt.Errorf("the source code is NOT INITIALIZED")
+		collector := metrics.NewCollector()
+		migrator, err := connectMigrator(ctx, func(rc *core.GomigerConfig) {
+			if cmd.IsSet("out-of-order") {
+				rc.OutOfOrder = core.OutOfOrderPolicy(cmd.String("out-of-order"))
+			}
+			if cmd.IsSet("metrics-textfile") {
+				rc.Observers = append(rc.Observers, collector)
+			}
+		})
+		if err != nil {
+			return err
 		}
-		migrator := NewMigrator(&core.GomigerConfig{})
-		if err := migrator.Connect(ctx); err != nil {
-			return fmt.Errorf("cannot connect to database: %w", err)
+		if cmd.Bool("dry-run") {
+			return printPlan(ctx, migrator, core.DirectionUp, cmd.Args().Get(0), steps)
 		}
-		if err := migrator.Up(ctx, cmd.Args().Get(0)); err != nil {
-			return fmt.Errorf("cannot migrate the database: %w", err)
+		if steps > 0 {
+			err = migrator.UpSteps(ctx, steps)
+		} else {
+			err = migrator.Up(ctx, cmd.Args().Get(0))
 		}
-		return nil
+		if err != nil {
+			err = fmt.Errorf("cannot migrate the database: %w", err)
+		}
+		if cmd.IsSet("metrics-textfile") {
+			// The metrics are written for the failed runs too.
+			err = errors.Join(err, collector.Refresh(ctx, migrator), collector.WriteTextfile(cmd.String("metrics-textfile")))
+		}
+		return err
 	},
 }
 
@@ -88,46 +239,371 @@ var migrateDownCmd = &cli.Command{
 	Name:    "down",
 	Aliases: []string{"d"},
 	Usage:   "migrate the database down to a version",
+	Flags: []cli.Flag{
+		dryRunFlag,
+		stepsFlag,
+		allowIrreversibleFlag,
+	},
 	Action: func(ctx context.Context, cmd *cli.Command) error {
-		rc, err := core.GetGomigerRC(rcPath)
+		steps, err := stepsOf(cmd)
 		if err != nil {
-			return fmt.Errorf("cannot load the gomiger.rc file: %w", err)
+			return err
 		}
-		if !generator.IsSrcCodeInitialized(rc) {
-			return fmt.Errorf("the source code is NOT INITIALIZED")
+		migrator, err := connectMigrator(ctx, allowIrreversible(cmd))
+		if err != nil {
+			return err
+		}
+		if cmd.Bool("dry-run") {
+			return printPlan(ctx, migrator, core.DirectionDown, cmd.Args().Get(0), steps)
+		}
+		if steps > 0 {
+			err = migrator.DownSteps(ctx, steps)
+		} else {
+			err = migrator.Down(ctx, cmd.Args().Get(0))
+		}
+		if err != nil {
+			return fmt.Errorf("cannot migrate the database: %w", err)
 		}
-		migrator := NewMigrator(&core.GomigerConfig{})
-		if err := migrator.Connect(ctx); err != nil {
-			return fmt.Errorf("cannot connect to database: %w", err)
+		return nil
+	},
+}
+
+var redoCmd = &cli.Command{
+	Name:  "redo",
+	Usage: "revert the last migration, then apply it again",
+	Flags: []cli.Flag{
+		allowIrreversibleFlag,
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		migrator, err := connectMigrator(ctx, allowIrreversible(cmd))
+		if err != nil {
+			return err
+		}
+		if err := migrator.Redo(ctx); err != nil {
+			return fmt.Errorf("cannot redo the migration: %w", err)
+		}
+		return nil
+	},
+}
+
+var resetCmd = &cli.Command{
+	Name:  "reset",
+	Usage: "revert all the migrations",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "yes",
+			Usage: "confirm that all the migrations are reverted",
+		},
+		allowIrreversibleFlag,
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if !cmd.Bool("yes") {
+			return fmt.Errorf("reset reverts all the migrations, confirm it with --yes")
+		}
+		migrator, err := connectMigrator(ctx, allowIrreversible(cmd))
+		if err != nil {
+			return err
+		}
+		if err := migrator.Reset(ctx); err != nil {
+			return fmt.Errorf("cannot reset the database: %w", err)
+		}
+		return nil
+	},
+}
+
+var gotoCmd = &cli.Command{
+	Name:      "goto",
+	Usage:     "migrate the database up or down to a version",
+	ArgsUsage: "<version>",
+	Flags: []cli.Flag{
+		allowIrreversibleFlag,
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		migrator, err := connectMigrator(ctx, allowIrreversible(cmd))
+		if err != nil {
+			return err
 		}
-		if err := migrator.Down(ctx, cmd.Args().Get(0)); err != nil {
+		if err := migrator.Goto(ctx, cmd.Args().Get(0)); err != nil {
 			return fmt.Errorf("cannot migrate the database: %w", err)
 		}
 		return nil
 	},
 }
 
+var baselineCmd = &cli.Command{
+	Name:      "baseline",
+	Usage:     "record the migrations until a version as applied, without executing them",
+	ArgsUsage: "<version>",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		migrator, err := connectMigrator(ctx)
+		if err != nil {
+			return err
+		}
+		if err := migrator.Baseline(ctx, cmd.Args().Get(0)); err != nil {
+			return fmt.Errorf("cannot baseline the database: %w", err)
+		}
+		return nil
+	},
+}
+
+// printPlan prints the migrations that a run to the target, or of a number of steps, would go through.
+func printPlan(ctx context.Context, migrator core.Gomiger, direction core.Direction, target string, steps int) error {
+	var plan *core.Plan
+	var err error
+	if steps > 0 {
+		plan, err = migrator.PlanSteps(ctx, direction, steps)
+	} else {
+		plan, err = migrator.Plan(ctx, direction, target)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot plan the migration: %w", err)
+	}
+	if len(plan.Runs()) == 0 {
+		fmt.Println("Nothing to migrate")
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tVERSION\tREASON")
+	for _, step := range plan.Steps {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", step.Action, step.Version, step.Reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(plan.OutOfOrder) > 0 {
+		fmt.Printf("Out of order: %s sort before the latest applied version %s\n", strings.Join(plan.OutOfOrder, ", "), plan.LatestApplied)
+	}
+	return nil
+}
+
 var getMigrationStatusCmd = &cli.Command{
 	Name:    "status",
 	Aliases: []string{"s"},
-	Usage:   "get the current migration status",
+	Usage:   "list the status of all migrations",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Value:   "table",
+			Usage:   "output format: table or json",
+		},
+	},
 	Action: func(ctx context.Context, cmd *cli.Command) error {
-		rc, err := core.GetGomigerRC(rcPath)
+		migrator, err := connectMigrator(ctx)
 		if err != nil {
-			return fmt.Errorf("cannot load the gomiger.rc file: %w", err)
+			return err
 		}
-		if !generator.IsSrcCodeInitialized(rc) {
-			return fmt.Errorf("the source code is NOT INITIALIZED")
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot get the migration status: %w", err)
+		}
+		switch cmd.String("output") {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(statuses)
+		case "table":
+			return printStatusTable(statuses)
+		default:
+			return fmt.Errorf("unknown output format: %s", cmd.String("output"))
+		}
+	},
+}
+
+// printStatusTable prints the migration statuses as a table.
+func printStatusTable(statuses []core.MigrationStatus) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tDURATION")
+	for _, status := range statuses {
+		appliedAt, duration := "-", "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Duration > 0 {
+			duration = status.Duration.String()
+		}
+		state := string(status.State)
+		if status.Baselined {
+			state += " (baseline)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status.Version, state, appliedAt, duration)
+	}
+	return w.Flush()
+}
+
+var forceCmd = &cli.Command{
+	Name:      "force",
+	Usage:     "set the status of a version without executing its migration",
+	ArgsUsage: "<version>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "status",
+			Usage:    "the status to set: applied or pending",
+			Required: true,
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		migrator, err := connectMigrator(ctx)
+		if err != nil {
+			return err
+		}
+		if err := migrator.Force(ctx, cmd.Args().Get(0), core.MigrationState(cmd.String("status"))); err != nil {
+			return fmt.Errorf("cannot force the version: %w", err)
+		}
+		return nil
+	},
+}
+
+var retryCmd = &cli.Command{
+	Name:      "retry",
+	Usage:     "apply a dirty or in progress migration again",
+	ArgsUsage: "<version>",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		migrator, err := connectMigrator(ctx)
+		if err != nil {
+			return err
+		}
+		if err := migrator.Retry(ctx, cmd.Args().Get(0)); err != nil {
+			return fmt.Errorf("cannot retry the migration: %w", err)
 		}
-		migrator := NewMigrator(&core.GomigerConfig{})
-		if err := migrator.Connect(ctx); err != nil {
-			return fmt.Errorf("cannot connect to database: %w", err)
+		return nil
+	},
+}
+
+var repairCmd = &cli.Command{
+	Name:  "repair",
+	Usage: "list the dirty and in progress migrations which need a recovery",
+	Flags: []cli.Flag{
+		&cli.DurationFlag{
+			Name:  "older-than",
+			Usage: "only list the migrations which started before this duration",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		migrator, err := connectMigrator(ctx)
+		if err != nil {
+			return err
 		}
-		schema, err := migrator.GetSchema(ctx, cmd.Args().Get(0))
+		schemas, err := migrator.Repair(ctx, cmd.Duration("older-than"))
 		if err != nil {
-			return fmt.Errorf("cannot get schema: %w", err)
+			return fmt.Errorf("cannot list the migrations to repair: %w", err)
+		}
+		if len(schemas) == 0 {
+			fmt.Println("Nothing to repair")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tSTATUS\tTIMESTAMP")
+		for _, schema := range schemas {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", schema.Version, schema.Status, schema.Timestamp.Format(time.RFC3339))
 		}
-		fmt.Printf("Version: %s, Status: %s\n", schema.Version, schema.Status)
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Println("Recover them with 'retry <version>' or 'force <version> --status applied|pending'")
 		return nil
 	},
 }
+
+var validateCmd = &cli.Command{
+	Name:  "validate",
+	Usage: "check that the applied migrations have not been modified since they were applied",
+	Action: func(ctx context.Context, _ *cli.Command) error {
+		migrator, err := connectMigrator(ctx)
+		if err != nil {
+			return err
+		}
+		mismatches, err := migrator.Validate(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot validate the migrations: %w", err)
+		}
+		if len(mismatches) == 0 {
+			fmt.Println("All applied migrations match their checksum")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tRECORDED\tCURRENT")
+		for _, mismatch := range mismatches {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", mismatch.Version, mismatch.Recorded, mismatch.Current)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		return fmt.Errorf("%d applied migration(s) have been modified since they were applied", len(mismatches))
+	},
+}
+
+var lockCmd = &cli.Command{
+	Name:  "lock",
+	Usage: "inspect the migration lock",
+	Commands: []*cli.Command{
+		{
+			Name:  "status",
+			Usage: "get the current holder of the migration lock",
+			Action: func(ctx context.Context, _ *cli.Command) error {
+				migrator, err := connectMigrator(ctx)
+				if err != nil {
+					return err
+				}
+				lock, err := migrator.LockStatus(ctx)
+				if err != nil {
+					return fmt.Errorf("cannot get the migration lock: %w", err)
+				}
+				if lock == nil {
+					fmt.Println("The migration lock is free")
+					return nil
+				}
+				state := "held"
+				if lock.IsExpired() {
+					state = "expired"
+				}
+				fmt.Printf("Owner: %s, Acquired at: %s, Expires at: %s (%s)\n",
+					lock.Owner, lock.AcquiredAt.Format(time.RFC3339), lock.ExpiresAt.Format(time.RFC3339), state)
+				return nil
+			},
+		},
+	},
+}
+
+var unlockCmd = &cli.Command{
+	Name:  "unlock",
+	Usage: "release the migration lock held by a crashed migrator",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "release the lock regardless of its owner",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if !cmd.Bool("force") {
+			return fmt.Errorf("the lock may be held by a running migrator, use --force to release it anyway")
+		}
+		migrator, err := connectMigrator(ctx)
+		if err != nil {
+			return err
+		}
+		if err := migrator.ForceUnlock(ctx); err != nil {
+			return fmt.Errorf("cannot release the migration lock: %w", err)
+		}
+		return nil
+	},
+}
+
+// connectMigrator loads the gomiger.rc file, then creates and connects the migrator.
+// The options override the gomiger.rc file for a single run.
+func connectMigrator(ctx context.Context, options ...func(rc *core.GomigerConfig)) (core.Gomiger, error) {
+	rc, err := core.GetGomigerRC(rcPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the gomiger.rc file: %w", err)
+	}
+	if !generator.IsSrcCodeInitialized(rc) {
+		return nil, fmt.Errorf("the source code is NOT INITIALIZED")
+	}
+	for _, option := range options {
+		option(rc)
+	}
+	migrator := NewMigrator(rc)
+	if err := migrator.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("cannot connect to database: %w", err)
+	}
+	return migrator, nil
+}
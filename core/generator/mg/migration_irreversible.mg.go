@@ -0,0 +1,23 @@
+//go:build ignore
+
+package main
+
+import (
+	"context"
+)
+
+//nolint:godoclint,revive
+func (m *Migrator) MigrationNameUp(ctx context.Context) error {
+	/** Your migration up code here: */
+	return nil
+}
+
+// This migration is irreversible: it has no down method.
+// Down runs refuse to cross it, unless they are run with --allow-irreversible.
+
+// AUTO GENERATED, DO NOT MODIFY!
+//
+//nolint:godoclint
+func (m *Migrator) MigrationNameVersion() string {
+	return "__VERSION__"
+}
@@ -23,9 +23,8 @@ func NewMigrator(config *core.GomigerConfig) core.Gomiger {
 		Config: config,
 	}
 
-	// ** Add your migrations here **
-	m.Migrations = []core.Migration{
-		// {Version: MigrationNameVersion(), Up: m.MigrationNameUp, Down: m.MigrationNameDown},
-	}
+	// The migrations are registered by the generator in registry.mg.go,
+	// on the `new` & `generate` commands.
+	m.Migrations = m.registeredMigrations()
 	return m
 }
@@ -0,0 +1,30 @@
+//go:build ignore
+
+package main
+
+import (
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/mysqlminger"
+)
+
+// Migrator is the main migrator struct.
+type Migrator struct {
+	// Mysqlminger does not run the migrations in transactions, as MySQL commits DDL statements implicitly.
+	// A failed migration is marked as dirty, recover it with the force or retry command.
+	*mysqlminger.Mysqlminger
+
+	Config *core.GomigerConfig
+}
+
+// NewMigrator creates a new migrator.
+func NewMigrator(config *core.GomigerConfig) core.Gomiger {
+	m := &Migrator{
+		Mysqlminger: mysqlminger.NewMysqlminger(config),
+		Config:      config,
+	}
+
+	// The migrations are registered by the generator in registry.mg.go,
+	// on the `new` & `generate` commands.
+	m.Migrations = m.registeredMigrations()
+	return m
+}
@@ -0,0 +1,30 @@
+//go:build ignore
+
+package main
+
+import (
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/pgminger"
+)
+
+// Migrator is the main migrator struct.
+type Migrator struct {
+	// Pgminger runs each migration in a transaction.
+	// Query the database with m.Conn(ctx) in the migrations, so the queries are part of it.
+	*pgminger.Pgminger
+
+	Config *core.GomigerConfig
+}
+
+// NewMigrator creates a new migrator.
+func NewMigrator(config *core.GomigerConfig) core.Gomiger {
+	m := &Migrator{
+		Pgminger: pgminger.NewPgminger(config),
+		Config:   config,
+	}
+
+	// The migrations are registered by the generator in registry.mg.go,
+	// on the `new` & `generate` commands.
+	m.Migrations = m.registeredMigrations()
+	return m
+}
@@ -5,8 +5,20 @@ package main
 //nolint:revive
 var MigrationScriptTemplateBase64 = `__MIGRATION_SCRIPT_TEMPLATE__`
 
+//nolint:revive
+var IrreversibleMigrationScriptTemplateBase64 = `__IRREVERSIBLE_MIGRATION_SCRIPT_TEMPLATE__`
+
 //nolint:revive
 var MigratorTemplateBase64 = `__MIGRATOR_TEMPLATE__`
 
+//nolint:revive
+var SqliteMigratorTemplateBase64 = `__SQLITE_MIGRATOR_TEMPLATE__`
+
+//nolint:revive
+var PostgresMigratorTemplateBase64 = `__POSTGRES_MIGRATOR_TEMPLATE__`
+
+//nolint:revive
+var MysqlMigratorTemplateBase64 = `__MYSQL_MIGRATOR_TEMPLATE__`
+
 //nolint:revive
 var CliTemplateBase64 = `__CLI_TEMPLATE__`
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+// RegistryFileName is the name of the generated registry file, in the migration folder.
+const RegistryFileName = "registry.mg.go"
+
+// migrationFuncRegexp matches the methods of a migration: captures its name (Migration_<ts>_<name>),
+// its timestamp and the method kind. The Down, BeforeUp, AfterUp, DependsOn & DisableTransaction methods
+// are optional, a migration without Down is irreversible.
+var migrationFuncRegexp = regexp.MustCompile(`^(Migration_(\d+)_\w*)_(Up|Down|BeforeUp|AfterUp|Version|DependsOn|DisableTransaction)$`)
+
+// migrationDecl is a migration declared in the source code of the migration folder.
+type migrationDecl struct {
+	// Name is the method prefix of the migration: Migration_<ts>_<name>.
+	Name string
+	// Version is the string returned by the Version method, or the timestamp of the name.
+	Version string
+	// Bodies are the formatted bodies of the migration methods, keyed by kind.
+	Bodies map[string]string
+}
+
+// scanMigrations parses the Go files of the migration folder, and lists the migrations sorted by version.
+func scanMigrations(path string) ([]*migrationDecl, error) {
+	files, err := filepath.Glob(filepath.Join(path, "*.go"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot list the migration files: %w", err)
+	}
+	declByName := map[string]*migrationDecl{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		fs := token.NewFileSet()
+		node, err := parser.ParseFile(fs, file, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the migration file %s: %w", file, err)
+		}
+		for _, decl := range node.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Body == nil {
+				continue
+			}
+			matches := migrationFuncRegexp.FindStringSubmatch(fn.Name.Name)
+			if matches == nil {
+				continue
+			}
+			name, timestamp, kind := matches[1], matches[2], matches[3]
+			mi, ok := declByName[name]
+			if !ok {
+				mi = &migrationDecl{Name: name, Version: timestamp, Bodies: map[string]string{}}
+				declByName[name] = mi
+			}
+			var body bytes.Buffer
+			if err := printer.Fprint(&body, fs, fn.Body); err != nil {
+				return nil, fmt.Errorf("cannot print the body of %s: %w", fn.Name.Name, err)
+			}
+			mi.Bodies[kind] = removeBlankLines(body.String())
+			if kind == "Version" {
+				if version, ok := returnedString(fn); ok {
+					mi.Version = version
+				}
+			}
+		}
+	}
+
+	migrations := make([]*migrationDecl, 0, len(declByName))
+	for _, mi := range declByName {
+		for _, kind := range []string{"Up", "Version"} {
+			if _, ok := mi.Bodies[kind]; !ok {
+				return nil, fmt.Errorf("migration %s has no %s method", mi.Name, kind)
+			}
+		}
+		migrations = append(migrations, mi)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		if migrations[i].Version != migrations[j].Version {
+			return migrations[i].Version < migrations[j].Version
+		}
+		return migrations[i].Name < migrations[j].Name
+	})
+	return migrations, nil
+}
+
+// returnedString returns the string literal of a `return "..."` function.
+func returnedString(fn *ast.FuncDecl) (string, bool) {
+	if len(fn.Body.List) != 1 {
+		return "", false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+func removeBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// GenRegistryFile scans the migration folder and writes the registry file,
+// which registers every migration with its checksum, sorted by version.
+func GenRegistryFile(rc *core.GomigerConfig) error {
+	migrations, err := scanMigrations(rc.Path)
+	if err != nil {
+		return err
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// THIS FILE IS GENERATED BY GOMIGER. PLEASE DO NOT MODIFY IT.\n//\n//nolint:revive\n")
+	fmt.Fprintf(&src, "package %s\n\n", rc.PkgName)
+	src.WriteString("import \"github.com/ParteeLabs/gomiger/core\"\n\n")
+	src.WriteString("// registeredMigrations returns the migrations of the package, sorted by version.\n")
+	src.WriteString("func (m *Migrator) registeredMigrations() []core.Migration {\n")
+	src.WriteString("return []core.Migration{\n")
+	for _, mi := range migrations {
+		src.WriteString("{\n")
+		fmt.Fprintf(&src, "Version: m.%s_Version(),\n", mi.Name)
+		fmt.Fprintf(&src, "Up: m.%s_Up,\n", mi.Name)
+		for _, kind := range []string{"Down", "BeforeUp", "AfterUp"} {
+			if _, ok := mi.Bodies[kind]; ok {
+				fmt.Fprintf(&src, "%s: m.%s_%s,\n", kind, mi.Name, kind)
+			}
+		}
+		fmt.Fprintf(&src, "Checksum: %q,\n", checksumOf(mi))
+		for _, kind := range []string{"DependsOn", "DisableTransaction"} {
+			if _, ok := mi.Bodies[kind]; ok {
+				fmt.Fprintf(&src, "%s: m.%s_%s(),\n", kind, mi.Name, kind)
+			}
+		}
+		src.WriteString("},\n")
+	}
+	src.WriteString("}\n}\n")
+
+	content, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("cannot format the registry file: %w", err)
+	}
+	//nolint:gosec
+	if err := os.WriteFile(filepath.Join(rc.Path, RegistryFileName), content, 0o644); err != nil {
+		return fmt.Errorf("cannot write the registry file: %w", err)
+	}
+	return nil
+}
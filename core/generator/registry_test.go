@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+func TestScanMigrations(t *testing.T) {
+	t.Run("lists the migrations sorted by version", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChecksumTestMigration(t, dir, checksumTestMigration)
+		older := strings.ReplaceAll(checksumTestMigration, "202401010000", "202312010000")
+		if err := os.WriteFile(filepath.Join(dir, "202312010000_create_users.mg.go"), []byte(older), 0o600); err != nil {
+			t.Fatalf("Failed to write the migration file: %v", err)
+		}
+
+		migrations, err := scanMigrations(dir)
+		if err != nil {
+			t.Fatalf("scanMigrations failed: %v", err)
+		}
+		if len(migrations) != 2 {
+			t.Fatalf("Expected 2 migrations, got: %d", len(migrations))
+		}
+		if migrations[0].Name != "Migration_202312010000_create_users" || migrations[1].Name != "Migration_202401010000_create_users" {
+			t.Errorf("Expected the migrations sorted by version, got: %s, %s", migrations[0].Name, migrations[1].Name)
+		}
+	})
+
+	t.Run("uses the version returned by the Version method", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChecksumTestMigration(t, dir, strings.Replace(checksumTestMigration, `return "202401010000"`, `return "v1"`, 1))
+
+		migrations, err := scanMigrations(dir)
+		if err != nil {
+			t.Fatalf("scanMigrations failed: %v", err)
+		}
+		if migrations[0].Version != "v1" {
+			t.Errorf("Expected version v1, got: %s", migrations[0].Version)
+		}
+	})
+
+	t.Run("returns error for an incomplete migration", func(t *testing.T) {
+		dir := t.TempDir()
+		incomplete := strings.Replace(checksumTestMigration, "_create_users_Up(", "_create_users_Apply(", 1)
+		writeChecksumTestMigration(t, dir, incomplete)
+
+		_, err := scanMigrations(dir)
+		if err == nil || !strings.Contains(err.Error(), "has no Up method") {
+			t.Errorf("Expected error for the missing Up method, got: %v", err)
+		}
+	})
+
+	t.Run("accepts an irreversible migration without Down method", func(t *testing.T) {
+		dir := t.TempDir()
+		irreversible := strings.Replace(checksumTestMigration, "_create_users_Down(", "_create_users_Rollback(", 1)
+		writeChecksumTestMigration(t, dir, irreversible)
+
+		migrations, err := scanMigrations(dir)
+		if err != nil {
+			t.Fatalf("scanMigrations failed: %v", err)
+		}
+		if _, ok := migrations[0].Bodies["Down"]; ok {
+			t.Error("Expected no Down body for the irreversible migration")
+		}
+	})
+
+	t.Run("ignores test files", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "migration_test.go"), []byte(checksumTestMigration), 0o600); err != nil {
+			t.Fatalf("Failed to write the test file: %v", err)
+		}
+
+		migrations, err := scanMigrations(dir)
+		if err != nil {
+			t.Fatalf("scanMigrations failed: %v", err)
+		}
+		if len(migrations) != 0 {
+			t.Errorf("Expected no migration, got: %d", len(migrations))
+		}
+	})
+}
+
+func TestGenRegistryFile(t *testing.T) {
+	t.Run("registers every migration with its checksum", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChecksumTestMigration(t, dir, checksumTestMigration)
+
+		rc := &core.GomigerConfig{Path: dir, PkgName: "migrations"}
+		if err := GenRegistryFile(rc); err != nil {
+			t.Fatalf("GenRegistryFile failed: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, RegistryFileName))
+		if err != nil {
+			t.Fatalf("Failed to read the registry file: %v", err)
+		}
+		checksums, err := ComputeChecksums(dir)
+		if err != nil {
+			t.Fatalf("ComputeChecksums failed: %v", err)
+		}
+		contentStr := string(content)
+		for _, expected := range []string{
+			"package migrations",
+			"Version:  m.Migration_202401010000_create_users_Version(),",
+			"Up:       m.Migration_202401010000_create_users_Up,",
+			"Down:     m.Migration_202401010000_create_users_Down,",
+			`Checksum: "` + checksums["202401010000"] + `",`,
+		} {
+			if !strings.Contains(contentStr, expected) {
+				t.Errorf("Registry file does not contain %q, got:\n%s", expected, contentStr)
+			}
+		}
+	})
+
+	t.Run("registers an irreversible migration without Down", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChecksumTestMigration(t, dir, strings.Replace(checksumTestMigration, "_create_users_Down(", "_create_users_Rollback(", 1))
+
+		rc := &core.GomigerConfig{Path: dir, PkgName: "migrations"}
+		if err := GenRegistryFile(rc); err != nil {
+			t.Fatalf("GenRegistryFile failed: %v", err)
+		}
+		content, err := os.ReadFile(filepath.Join(dir, RegistryFileName))
+		if err != nil {
+			t.Fatalf("Failed to read the registry file: %v", err)
+		}
+		if strings.Contains(string(content), "Down:") {
+			t.Errorf("Registry file registers a Down for the irreversible migration, got:\n%s", content)
+		}
+	})
+
+	t.Run("registers the optional methods", func(t *testing.T) {
+		dir := t.TempDir()
+		writeChecksumTestMigration(t, dir, checksumTestMigration+`
+func (m *Migrator) Migration_202401010000_create_users_DependsOn() []string {
+	return []string{"202312010000"}
+}
+
+func (m *Migrator) Migration_202401010000_create_users_DisableTransaction() bool {
+	return true
+}
+
+func (m *Migrator) Migration_202401010000_create_users_BeforeUp(ctx context.Context) error {
+	return nil
+}
+
+func (m *Migrator) Migration_202401010000_create_users_AfterUp(ctx context.Context) error {
+	return nil
+}
+`)
+
+		rc := &core.GomigerConfig{Path: dir, PkgName: "migrations"}
+		if err := GenRegistryFile(rc); err != nil {
+			t.Fatalf("GenRegistryFile failed: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, RegistryFileName))
+		if err != nil {
+			t.Fatalf("Failed to read the registry file: %v", err)
+		}
+		for _, expected := range []string{
+			"DependsOn:          m.Migration_202401010000_create_users_DependsOn(),",
+			"DisableTransaction: m.Migration_202401010000_create_users_DisableTransaction(),",
+			"BeforeUp:           m.Migration_202401010000_create_users_BeforeUp,",
+			"AfterUp:            m.Migration_202401010000_create_users_AfterUp,",
+		} {
+			if !strings.Contains(string(content), expected) {
+				t.Errorf("Registry file does not contain %q, got:\n%s", expected, content)
+			}
+		}
+	})
+
+	t.Run("writes an empty registry", func(t *testing.T) {
+		dir := t.TempDir()
+
+		rc := &core.GomigerConfig{Path: dir, PkgName: "migrations"}
+		if err := GenRegistryFile(rc); err != nil {
+			t.Fatalf("GenRegistryFile failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, RegistryFileName)); err != nil {
+			t.Errorf("Registry file was not created: %v", err)
+		}
+	})
+}
@@ -12,7 +12,11 @@
 //
 // Template files processed:
 //   - migration.mg.go: Template for migration scripts
+//   - migration_irreversible.mg.go: Template for irreversible migration scripts, without down method
 //   - migrator.mg.go: Template for the migrator implementation
+//   - migrator_sqlite.mg.go: Template for the migrator implementation with the SQLite plugin
+//   - migrator_postgres.mg.go: Template for the migrator implementation with the PostgreSQL plugin
+//   - migrator_mysql.mg.go: Template for the migrator implementation with the MySQL plugin
 //   - cli.mg.go: Template for CLI interface
 //
 // The output file contents.mg.go is formatted according to Go standards
@@ -20,6 +24,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"go/ast"
@@ -46,20 +51,54 @@ func main() {
 		fmt.Println("Error reading template file migration.mg.go:", err)
 		return
 	}
+	irreversibleMigrationTemplateContent, err := os.ReadFile("./core/generator/mg/migration_irreversible.mg.go")
+	if err != nil {
+		fmt.Println("Error reading template file migration_irreversible.mg.go:", err)
+		return
+	}
 	migratorTemplateContent, err := os.ReadFile("./core/generator/mg/migrator.mg.go")
 	if err != nil {
 		fmt.Println("Error reading template file migrator.mg.go:", err)
 		return
 	}
+	sqliteMigratorTemplateContent, err := os.ReadFile("./core/generator/mg/migrator_sqlite.mg.go")
+	if err != nil {
+		fmt.Println("Error reading template file migrator_sqlite.mg.go:", err)
+		return
+	}
+	postgresMigratorTemplateContent, err := os.ReadFile("./core/generator/mg/migrator_postgres.mg.go")
+	if err != nil {
+		fmt.Println("Error reading template file migrator_postgres.mg.go:", err)
+		return
+	}
+	mysqlMigratorTemplateContent, err := os.ReadFile("./core/generator/mg/migrator_mysql.mg.go")
+	if err != nil {
+		fmt.Println("Error reading template file migrator_mysql.mg.go:", err)
+		return
+	}
 	cliTemplateContent, err := os.ReadFile("./core/generator/mg/cli.mg.go")
 	if err != nil {
 		fmt.Println("Error reading template file cli.mg.go:", err)
 		return
 	}
 
+	/// The templates are excluded from the build by a constraint, which must not be shipped.
+	migrationTemplateContent = stripBuildConstraint(migrationTemplateContent)
+	irreversibleMigrationTemplateContent = stripBuildConstraint(irreversibleMigrationTemplateContent)
+	migratorTemplateContent = stripBuildConstraint(migratorTemplateContent)
+	sqliteMigratorTemplateContent = stripBuildConstraint(sqliteMigratorTemplateContent)
+	postgresMigratorTemplateContent = stripBuildConstraint(postgresMigratorTemplateContent)
+	mysqlMigratorTemplateContent = stripBuildConstraint(mysqlMigratorTemplateContent)
+	cliTemplateContent = stripBuildConstraint(cliTemplateContent)
+
 	/// Parse the skeleton then add the templates
+	skeletonContent, err := os.ReadFile("./core/generator/mg/skeleton.go")
+	if err != nil {
+		fmt.Println("Error reading skeleton file skeleton.go:", err)
+		return
+	}
 	fs := token.NewFileSet()
-	skeleton, err := parser.ParseFile(fs, "./core/generator/mg/skeleton.go", nil, parser.ParseComments)
+	skeleton, err := parser.ParseFile(fs, "./core/generator/mg/skeleton.go", stripBuildConstraint(skeletonContent), parser.ParseComments)
 	if err != nil {
 		fmt.Println("Error parsing template file migrator.mg.go:", err)
 		return
@@ -71,9 +110,21 @@ func main() {
 		if nf, ok := n.(*ast.BasicLit); ok && nf.Value == "`__MIGRATION_SCRIPT_TEMPLATE__`" {
 			nf.Value = fmt.Sprintf("`%s`", base64.StdEncoding.EncodeToString(migrationTemplateContent))
 		}
+		if nf, ok := n.(*ast.BasicLit); ok && nf.Value == "`__IRREVERSIBLE_MIGRATION_SCRIPT_TEMPLATE__`" {
+			nf.Value = fmt.Sprintf("`%s`", base64.StdEncoding.EncodeToString(irreversibleMigrationTemplateContent))
+		}
 		if nf, ok := n.(*ast.BasicLit); ok && nf.Value == "`__MIGRATOR_TEMPLATE__`" {
 			nf.Value = fmt.Sprintf("`%s`", base64.StdEncoding.EncodeToString(migratorTemplateContent))
 		}
+		if nf, ok := n.(*ast.BasicLit); ok && nf.Value == "`__SQLITE_MIGRATOR_TEMPLATE__`" {
+			nf.Value = fmt.Sprintf("`%s`", base64.StdEncoding.EncodeToString(sqliteMigratorTemplateContent))
+		}
+		if nf, ok := n.(*ast.BasicLit); ok && nf.Value == "`__POSTGRES_MIGRATOR_TEMPLATE__`" {
+			nf.Value = fmt.Sprintf("`%s`", base64.StdEncoding.EncodeToString(postgresMigratorTemplateContent))
+		}
+		if nf, ok := n.(*ast.BasicLit); ok && nf.Value == "`__MYSQL_MIGRATOR_TEMPLATE__`" {
+			nf.Value = fmt.Sprintf("`%s`", base64.StdEncoding.EncodeToString(mysqlMigratorTemplateContent))
+		}
 		if nf, ok := n.(*ast.BasicLit); ok && nf.Value == "`__CLI_TEMPLATE__`" {
 			nf.Value = fmt.Sprintf("`%s`", base64.StdEncoding.EncodeToString(cliTemplateContent))
 		}
@@ -96,3 +147,8 @@ func main() {
 		panic(err)
 	}
 }
+
+// stripBuildConstraint removes the "//go:build ignore" line of a template file.
+func stripBuildConstraint(content []byte) []byte {
+	return bytes.TrimPrefix(content, []byte("//go:build ignore\n\n"))
+}
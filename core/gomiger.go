@@ -2,9 +2,17 @@ package core
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrSchemaNotFound must be returned by GetSchema when a version has never been applied.
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// ErrIrreversible is returned by the down runs which cross an irreversible migration, unless it is allowed.
+// A Down function may return it too, when the migration cannot be undone.
+var ErrIrreversible = errors.New("irreversible migration")
+
 // SchemaStatus is the status of the schema
 type SchemaStatus string
 
@@ -22,16 +30,50 @@ type Schema struct {
 	Version   string       `json:"version" bson:"version" validate:"required"`
 	Timestamp time.Time    `json:"timestamp" bson:"timestamp" validate:"required"`
 	Status    SchemaStatus `json:"status" bson:"status" validate:"required"`
+	// Duration is the execution time of an applied migration.
+	Duration time.Duration `json:"duration,omitempty" bson:"duration,omitempty"`
+	// Checksum is the checksum of the migration code when it was applied.
+	Checksum string `json:"checksum,omitempty" bson:"checksum,omitempty"`
+	// Baselined is for a version recorded as applied by Baseline, without executing its migration.
+	Baselined bool `json:"baselined,omitempty" bson:"baselined,omitempty"`
+	// AppliedBy is the user & hostname of the process which recorded the schema, e.g. deploy@ci-runner-1.
+	AppliedBy string `json:"applied_by,omitempty" bson:"applied_by,omitempty"`
+	// GomigerVersion is the version of gomiger which recorded the schema.
+	GomigerVersion string `json:"gomiger_version,omitempty" bson:"gomiger_version,omitempty"`
+	// AppVersion is the build version of the application which recorded the schema.
+	AppVersion string `json:"app_version,omitempty" bson:"app_version,omitempty"`
+	// Direction is the direction of the last execution of the migration, down for a failed revert.
+	Direction Direction `json:"direction,omitempty" bson:"direction,omitempty"`
+	// Error is the error message of a dirty migration.
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
 }
 
 // Gomiger is the interface for the migrator
 type Gomiger interface {
 	Up(ctx context.Context, toVersion string) error
 	Down(ctx context.Context, atVersion string) error
+	UpSteps(ctx context.Context, n int) error
+	DownSteps(ctx context.Context, n int) error
+	Redo(ctx context.Context) error
+	Reset(ctx context.Context) error
+	Goto(ctx context.Context, version string) error
+	Baseline(ctx context.Context, version string) error
+	Plan(ctx context.Context, direction Direction, target string) (*Plan, error)
+	PlanSteps(ctx context.Context, direction Direction, n int) (*Plan, error)
 	Connect(ctx context.Context) error
 	GetSchema(ctx context.Context, version string) (*Schema, error)
+	ListSchemas(ctx context.Context) ([]Schema, error)
+	SaveSchema(ctx context.Context, schema Schema) error
+	DeleteSchema(ctx context.Context, version string) error
+	Status(ctx context.Context) ([]MigrationStatus, error)
+	Force(ctx context.Context, version string, state MigrationState) error
+	Retry(ctx context.Context, version string) error
+	Repair(ctx context.Context, olderThan time.Duration) ([]Schema, error)
+	Validate(ctx context.Context) ([]ChecksumMismatch, error)
 	ApplyMigration(ctx context.Context, mi Migration) error
 	RevertMigration(ctx context.Context, mi Migration) error
+	LockStatus(ctx context.Context) (*Lock, error)
+	ForceUnlock(ctx context.Context) error
 }
 
 // MutationFunc is a function that applies a migration.
@@ -41,5 +83,18 @@ type MutationFunc = func(context context.Context) error
 type Migration struct {
 	Version string
 	Up      MutationFunc
-	Down    MutationFunc
+	// Down is nil for an irreversible migration.
+	Down MutationFunc
+	// Checksum is the checksum of the Up & Down code, computed by the generator.
+	Checksum string
+	// DependsOn lists the versions which must be applied before this migration.
+	DependsOn []string
+	// DisableTransaction runs the migration outside of a transaction in the transactional mode,
+	// e.g. for DDL operations which the database cannot run in a transaction.
+	DisableTransaction bool
+	// BeforeUp is run before the migration is applied, outside of its transaction. An error cancels the migration.
+	BeforeUp MutationFunc
+	// AfterUp is run after the migration is applied, outside of its transaction.
+	// An error fails the run, but the migration stays applied.
+	AfterUp MutationFunc
 }
@@ -0,0 +1,156 @@
+// Package gomigertest provides a test harness which proves that the migrations of a migrator are reversible.
+// Each migration is applied, reverted & applied again, and the state of the database is compared between the steps
+// with the snapshots of a Snapshotter, usually provided by the database plugin.
+package gomigertest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+// Snapshot is the state of a database: the definition of its objects (collections, tables, indexes…) by name.
+// The schema store is excluded, as it changes on every run.
+type Snapshot = map[string]string
+
+// Snapshotter captures the state of a database.
+type Snapshotter interface {
+	Snapshot(ctx context.Context) (Snapshot, error)
+}
+
+// SnapshotFunc adapts a function to a Snapshotter.
+type SnapshotFunc func(ctx context.Context) (Snapshot, error)
+
+// Snapshot implements Snapshotter.
+func (f SnapshotFunc) Snapshot(ctx context.Context) (Snapshot, error) {
+	return f(ctx)
+}
+
+// Stage is the step of the roundtrip where a migration is found irreversible.
+type Stage string
+
+var (
+	// StageDown is for a Down which does not restore the state before Up
+	StageDown Stage = "down"
+	// StageReapply is for an Up which does not reproduce the state of the first Up once reverted
+	StageReapply Stage = "reapply"
+)
+
+// IrreversibleError reports a migration which is not cleanly reversible.
+type IrreversibleError struct {
+	Version string
+	Stage   Stage
+	// Diff lists the objects which differ, see Diff.
+	Diff []string
+}
+
+func (e *IrreversibleError) Error() string {
+	switch e.Stage {
+	case StageDown:
+		return fmt.Sprintf("migration %s is not reversible, its down does not restore the state before its up:\n%s", e.Version, strings.Join(e.Diff, "\n"))
+	default:
+		return fmt.Sprintf("migration %s is not reversible, its up does not reproduce the same state once reverted:\n%s", e.Version, strings.Join(e.Diff, "\n"))
+	}
+}
+
+// Diff lists the objects which differ between two snapshots, sorted by name:
+// "+ name: definition" for an added object, "- name: definition" for a removed one
+// and "~ name: before -> after" for a changed one.
+func Diff(before, after Snapshot) []string {
+	diff := []string{}
+	for name, definition := range before {
+		changed, ok := after[name]
+		switch {
+		case !ok:
+			diff = append(diff, fmt.Sprintf("- %s: %s", name, definition))
+		case changed != definition:
+			diff = append(diff, fmt.Sprintf("~ %s: %s -> %s", name, definition, changed))
+		}
+	}
+	for name, definition := range after {
+		if _, ok := before[name]; !ok {
+			diff = append(diff, fmt.Sprintf("+ %s: %s", name, definition))
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i][2:] < diff[j][2:] })
+	return diff
+}
+
+// Verify runs each pending migration of the migrator up, down & up again, in the order of Up.
+// It returns an *IrreversibleError for the first migration whose down does not restore the state before its up,
+// or whose second up does not reproduce the state of the first one.
+// The irreversible migrations, without Down, are only applied.
+// Run it against an empty database: the migrations are left applied.
+func Verify(ctx context.Context, migrator core.Gomiger, snapshotter Snapshotter) error {
+	plan, err := migrator.Plan(ctx, core.DirectionUp, "")
+	if err != nil {
+		return fmt.Errorf("failed to plan the migrations: %w", err)
+	}
+	for _, step := range plan.Runs() {
+		if step.Migration.Down == nil {
+			if err := migrator.Up(ctx, step.Version); err != nil {
+				return fmt.Errorf("failed to apply migration %s: %w", step.Version, err)
+			}
+			continue
+		}
+		if err := roundtrip(ctx, migrator, snapshotter, step.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Roundtrip runs Verify from a test, and fails it if a migration is not reversible.
+func Roundtrip(t testing.TB, migrator core.Gomiger, snapshotter Snapshotter) {
+	t.Helper()
+	if err := Verify(context.Background(), migrator, snapshotter); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func roundtrip(ctx context.Context, migrator core.Gomiger, snapshotter Snapshotter, version string) error {
+	before, err := snapshot(ctx, snapshotter, version)
+	if err != nil {
+		return err
+	}
+	if err := migrator.Up(ctx, version); err != nil {
+		return fmt.Errorf("failed to apply migration %s: %w", version, err)
+	}
+	applied, err := snapshot(ctx, snapshotter, version)
+	if err != nil {
+		return err
+	}
+	if err := migrator.Down(ctx, version); err != nil {
+		return fmt.Errorf("failed to revert migration %s: %w", version, err)
+	}
+	reverted, err := snapshot(ctx, snapshotter, version)
+	if err != nil {
+		return err
+	}
+	if diff := Diff(before, reverted); len(diff) > 0 {
+		return &IrreversibleError{Version: version, Stage: StageDown, Diff: diff}
+	}
+	if err := migrator.Up(ctx, version); err != nil {
+		return fmt.Errorf("failed to reapply migration %s: %w", version, err)
+	}
+	reapplied, err := snapshot(ctx, snapshotter, version)
+	if err != nil {
+		return err
+	}
+	if diff := Diff(applied, reapplied); len(diff) > 0 {
+		return &IrreversibleError{Version: version, Stage: StageReapply, Diff: diff}
+	}
+	return nil
+}
+
+func snapshot(ctx context.Context, snapshotter Snapshotter, version string) (Snapshot, error) {
+	state, err := snapshotter.Snapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database around migration %s: %w", version, err)
+	}
+	return state, nil
+}
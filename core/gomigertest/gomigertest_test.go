@@ -0,0 +1,153 @@
+package gomigertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/memminger"
+	"github.com/stretchr/testify/suite"
+)
+
+type GomigertestTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	migrator *memminger.Memminger
+	// database is the state mutated by the test migrations.
+	database Snapshot
+}
+
+func (s *GomigertestTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.database = Snapshot{}
+	s.migrator = memminger.NewMemminger(nil)
+	s.migrator.Migrations = []core.Migration{
+		s.create("1.0.0", "table users", "id"),
+		s.create("2.0.0", "index users.email", "unique"),
+	}
+}
+
+// create returns a migration which creates an object on Up & drops it on Down.
+func (s *GomigertestTestSuite) create(version, name, definition string) core.Migration {
+	return core.Migration{
+		Version: version,
+		Up: func(ctx context.Context) error {
+			s.database[name] = definition
+			return nil
+		},
+		Down: func(ctx context.Context) error {
+			delete(s.database, name)
+			return nil
+		},
+	}
+}
+
+func (s *GomigertestTestSuite) snapshotter() Snapshotter {
+	return SnapshotFunc(func(ctx context.Context) (Snapshot, error) {
+		return maps.Clone(s.database), nil
+	})
+}
+
+func (s *GomigertestTestSuite) TestVerify_Reversible() {
+	err := Verify(s.ctx, s.migrator, s.snapshotter())
+	s.Require().NoError(err)
+	// Each migration is applied twice & reverted once, and left applied.
+	s.Require().Equal([]string{"1.0.0", "1.0.0", "2.0.0", "2.0.0"}, s.migrator.Applied())
+	s.Require().Equal([]string{"1.0.0", "2.0.0"}, s.migrator.Reverted())
+	s.Require().Equal(Snapshot{"table users": "id", "index users.email": "unique"}, s.database)
+}
+
+func (s *GomigertestTestSuite) TestVerify_SkipsIrreversible() {
+	s.migrator.Migrations[0].Down = nil
+	err := Verify(s.ctx, s.migrator, s.snapshotter())
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"1.0.0", "2.0.0", "2.0.0"}, s.migrator.Applied())
+	s.Require().Equal([]string{"2.0.0"}, s.migrator.Reverted())
+}
+
+func (s *GomigertestTestSuite) TestVerify_DownDoesNotRestore() {
+	s.migrator.Migrations[1].Down = func(ctx context.Context) error { return nil }
+	err := Verify(s.ctx, s.migrator, s.snapshotter())
+	var irreversible *IrreversibleError
+	s.Require().ErrorAs(err, &irreversible)
+	s.Require().Equal("2.0.0", irreversible.Version)
+	s.Require().Equal(StageDown, irreversible.Stage)
+	s.Require().Equal([]string{"+ index users.email: unique"}, irreversible.Diff)
+	s.ErrorContains(err, "migration 2.0.0 is not reversible, its down does not restore the state before its up")
+}
+
+func (s *GomigertestTestSuite) TestVerify_ReapplyDiffers() {
+	runs := 0
+	s.migrator.Migrations[0].Up = func(ctx context.Context) error {
+		runs++
+		s.database["table users"] = fmt.Sprintf("id, column_%d", runs)
+		return nil
+	}
+	err := Verify(s.ctx, s.migrator, s.snapshotter())
+	var irreversible *IrreversibleError
+	s.Require().ErrorAs(err, &irreversible)
+	s.Require().Equal("1.0.0", irreversible.Version)
+	s.Require().Equal(StageReapply, irreversible.Stage)
+	s.Require().Equal([]string{"~ table users: id, column_1 -> id, column_2"}, irreversible.Diff)
+}
+
+func (s *GomigertestTestSuite) TestVerify_SkipsAppliedMigrations() {
+	s.migrator.Seed(core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	err := Verify(s.ctx, s.migrator, s.snapshotter())
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"2.0.0"}, s.migrator.Reverted())
+}
+
+func (s *GomigertestTestSuite) TestVerify_UpError() {
+	errUp := errors.New("up failed")
+	s.migrator.Migrations[0].Up = func(ctx context.Context) error { return errUp }
+	err := Verify(s.ctx, s.migrator, s.snapshotter())
+	s.Require().ErrorIs(err, errUp)
+	s.ErrorContains(err, "failed to apply migration 1.0.0")
+}
+
+func (s *GomigertestTestSuite) TestVerify_DownError() {
+	errDown := errors.New("down failed")
+	s.migrator.Migrations[0].Down = func(ctx context.Context) error { return errDown }
+	err := Verify(s.ctx, s.migrator, s.snapshotter())
+	s.Require().ErrorIs(err, errDown)
+	s.ErrorContains(err, "failed to revert migration 1.0.0")
+}
+
+func (s *GomigertestTestSuite) TestVerify_SnapshotError() {
+	errSnapshot := errors.New("snapshot failed")
+	err := Verify(s.ctx, s.migrator, SnapshotFunc(func(ctx context.Context) (Snapshot, error) {
+		return nil, errSnapshot
+	}))
+	s.Require().ErrorIs(err, errSnapshot)
+	s.ErrorContains(err, "failed to snapshot the database around migration 1.0.0")
+}
+
+func (s *GomigertestTestSuite) TestVerify_InvalidDependencies() {
+	s.migrator.Migrations[0].DependsOn = []string{"3.0.0"}
+	err := Verify(s.ctx, s.migrator, s.snapshotter())
+	s.Require().Error(err)
+	s.ErrorContains(err, "failed to plan the migrations")
+}
+
+func (s *GomigertestTestSuite) TestRoundtrip() {
+	Roundtrip(s.T(), s.migrator, s.snapshotter())
+	s.Require().Len(s.migrator.Schemas(), 2)
+}
+
+func (s *GomigertestTestSuite) TestDiff() {
+	s.Require().Empty(Diff(Snapshot{"a": "1"}, Snapshot{"a": "1"}))
+	s.Require().Equal([]string{
+		"- a: 1",
+		"~ b: 2 -> 3",
+		"+ c: 4",
+	}, Diff(Snapshot{"a": "1", "b": "2"}, Snapshot{"b": "3", "c": "4"}))
+}
+
+func TestGomigertestTestSuite(t *testing.T) {
+	suite.Run(t, new(GomigertestTestSuite))
+}
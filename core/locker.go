@@ -0,0 +1,170 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultLockTTL is the lease duration of the migration lock when BaseMigrator.LockTTL is not set.
+const DefaultLockTTL = 30 * time.Second
+
+// lockRetryInterval is the delay between two attempts to acquire a held lock.
+var lockRetryInterval = time.Second
+
+var (
+	// ErrLockHeld is returned by a Locker when the lock is held by another owner.
+	ErrLockHeld = errors.New("migration lock is held by another owner")
+	// ErrLockLost is returned by a Locker when the lock is not owned by the caller anymore.
+	ErrLockLost = errors.New("migration lock is lost")
+	// ErrLockUnsupported is returned when the migrator has no Locker.
+	ErrLockUnsupported = errors.New("migration lock is not supported by this migrator")
+)
+
+// Lock is the state of the migration lock.
+type Lock struct {
+	Owner      string    `json:"owner" bson:"owner"`
+	AcquiredAt time.Time `json:"acquired_at" bson:"acquired_at"`
+	// ExpiresAt is the end of the lease. The locks held by a session, as in pgminger & mysqlminger, have no lease:
+	// they report now + DefaultLockTTL while they are held.
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// IsExpired reports whether the lease of the lock is over.
+func (l *Lock) IsExpired() bool {
+	return !l.ExpiresAt.After(time.Now())
+}
+
+// Locker is a distributed lock backend, usually provided by a database plugin.
+// The lock is a lease: a holder must refresh it before its TTL is over, or another owner may take it.
+type Locker interface {
+	// AcquireLock takes the lock for the owner, returns ErrLockHeld if another owner holds an unexpired lease.
+	AcquireLock(ctx context.Context, owner string, ttl time.Duration) error
+	// RefreshLock extends the lease of the owner, returns ErrLockLost if the owner does not hold the lock.
+	RefreshLock(ctx context.Context, owner string, ttl time.Duration) error
+	// ReleaseLock releases the lock if it is held by the owner.
+	ReleaseLock(ctx context.Context, owner string) error
+	// GetLock returns the current lock, nil if nobody holds it.
+	GetLock(ctx context.Context) (*Lock, error)
+	// ForceReleaseLock releases the lock regardless of its owner.
+	ForceReleaseLock(ctx context.Context) error
+}
+
+func defaultLockOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
+
+func (b *BaseMigrator) lockTTL() time.Duration {
+	if b.LockTTL <= 0 {
+		return DefaultLockTTL
+	}
+	return b.LockTTL
+}
+
+// lockOwner returns the owner of a run. The lockers are re-entrant for an owner,
+// so the default owner has a random suffix: two runs of a process do not share the lock.
+func (b *BaseMigrator) lockOwner() string {
+	if b.LockOwner != "" {
+		return b.LockOwner
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s:%x", defaultLockOwner(), suffix)
+}
+
+// lock acquires the migration lock and keeps it alive until the returned unlock function is called.
+// The returned context is canceled when the lock is lost, so running migrations are stopped.
+func (b *BaseMigrator) lock(ctx context.Context) (context.Context, func() error, error) {
+	if b.Locker == nil {
+		return ctx, func() error { return nil }, nil
+	}
+	owner, ttl := b.lockOwner(), b.lockTTL()
+	for {
+		err := b.Locker.AcquireLock(ctx, owner, ttl)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrLockHeld) {
+			return nil, nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("failed to acquire migration lock: %w", errors.Join(err, ctx.Err()))
+		case <-time.After(lockRetryInterval):
+		}
+	}
+
+	lockCtx, cancel := context.WithCancelCause(ctx)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		refreshedAt := time.Now()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				err := b.Locker.RefreshLock(lockCtx, owner, ttl)
+				if err == nil {
+					refreshedAt = time.Now()
+					continue
+				}
+				// Transient errors are retried until the lease is over.
+				if errors.Is(err, ErrLockLost) || time.Since(refreshedAt) >= ttl {
+					cancel(fmt.Errorf("failed to refresh migration lock: %w", err))
+					return
+				}
+			}
+		}
+	}()
+
+	unlock := func() error {
+		close(stop)
+		<-stopped
+		lostErr := context.Cause(lockCtx)
+		cancel(nil)
+		if errors.Is(lostErr, context.Canceled) || errors.Is(lostErr, context.DeadlineExceeded) {
+			// The parent context is done, it is not a lock failure.
+			lostErr = nil
+		}
+		if err := b.Locker.ReleaseLock(context.WithoutCancel(ctx), owner); err != nil {
+			return errors.Join(lostErr, fmt.Errorf("failed to release migration lock: %w", err))
+		}
+		return lostErr
+	}
+	return lockCtx, unlock, nil
+}
+
+// LockStatus returns the current migration lock, nil if it is free.
+func (b *BaseMigrator) LockStatus(ctx context.Context) (*Lock, error) {
+	if b.Locker == nil {
+		return nil, ErrLockUnsupported
+	}
+	lock, err := b.Locker.GetLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration lock: %w", err)
+	}
+	return lock, nil
+}
+
+// ForceUnlock releases the migration lock regardless of its owner.
+// Use it to recover from a crashed holder.
+func (b *BaseMigrator) ForceUnlock(ctx context.Context) error {
+	if b.Locker == nil {
+		return ErrLockUnsupported
+	}
+	if err := b.Locker.ForceReleaseLock(ctx); err != nil {
+		return fmt.Errorf("failed to release migration lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockLocker struct {
+	mock.Mock
+}
+
+func (m *MockLocker) AcquireLock(ctx context.Context, owner string, ttl time.Duration) error {
+	args := m.Called(ctx, owner, ttl)
+	return args.Error(0)
+}
+
+func (m *MockLocker) RefreshLock(ctx context.Context, owner string, ttl time.Duration) error {
+	args := m.Called(ctx, owner, ttl)
+	return args.Error(0)
+}
+
+func (m *MockLocker) ReleaseLock(ctx context.Context, owner string) error {
+	args := m.Called(ctx, owner)
+	return args.Error(0)
+}
+
+func (m *MockLocker) GetLock(ctx context.Context) (*Lock, error) {
+	args := m.Called(ctx)
+	lock, _ := args.Get(0).(*Lock)
+	return lock, args.Error(1)
+}
+
+func (m *MockLocker) ForceReleaseLock(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+type LockerTestSuite struct {
+	suite.Suite
+	methods  *MockAbstractMethods
+	locker   *MockLocker
+	migrator *BaseMigrator
+}
+
+func (s *LockerTestSuite) SetupTest() {
+	lockRetryInterval = time.Millisecond
+	s.methods = &MockAbstractMethods{}
+	s.locker = &MockLocker{}
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.methods,
+		Migrations: []Migration{
+			{Version: "20240101_initial"},
+		},
+		Locker:    s.locker,
+		LockOwner: "test-owner",
+	}
+}
+
+func (s *LockerTestSuite) TestUp_AcquiresAndReleasesLock() {
+	s.locker.On("AcquireLock", mock.Anything, "test-owner", DefaultLockTTL).Return(nil).Once()
+	s.locker.On("ReleaseLock", mock.Anything, "test-owner").Return(nil).Once()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: Applied}, nil).Once()
+
+	err := s.migrator.Up(context.Background(), "")
+	s.NoError(err)
+	s.locker.AssertExpectations(s.T())
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *LockerTestSuite) TestUp_DefaultLockOwner() {
+	s.migrator.LockOwner = ""
+	acquired, released := []string{}, []string{}
+	isProcessOwner := mock.MatchedBy(func(owner string) bool { return strings.HasPrefix(owner, defaultLockOwner()+":") })
+	s.locker.On("AcquireLock", mock.Anything, isProcessOwner, DefaultLockTTL).
+		Run(func(args mock.Arguments) { acquired = append(acquired, args.String(1)) }).Return(nil).Twice()
+	s.locker.On("ReleaseLock", mock.Anything, isProcessOwner).
+		Run(func(args mock.Arguments) { released = append(released, args.String(1)) }).Return(nil).Twice()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: Applied}, nil).Twice()
+
+	s.NoError(s.migrator.Up(context.Background(), ""))
+	s.NoError(s.migrator.Up(context.Background(), ""))
+	// Each run has its own owner, and the migrator is not mutated, so concurrent runs do not share the lock.
+	s.NotEqual(acquired[0], acquired[1])
+	s.Equal(acquired, released)
+	s.Empty(s.migrator.LockOwner)
+	s.locker.AssertExpectations(s.T())
+}
+
+func (s *LockerTestSuite) TestDown_AcquiresAndReleasesLock() {
+	s.locker.On("AcquireLock", mock.Anything, "test-owner", DefaultLockTTL).Return(nil).Once()
+	s.locker.On("ReleaseLock", mock.Anything, "test-owner").Return(nil).Once()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: InProgress}, nil).Once()
+
+	err := s.migrator.Down(context.Background(), "20240101_initial")
+	s.NoError(err)
+	s.locker.AssertExpectations(s.T())
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *LockerTestSuite) TestUp_WaitsForHeldLock() {
+	s.locker.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(ErrLockHeld).Twice()
+	s.locker.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	s.locker.On("ReleaseLock", mock.Anything, mock.Anything).Return(nil).Once()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: Applied}, nil).Once()
+
+	err := s.migrator.Up(context.Background(), "")
+	s.NoError(err)
+	s.locker.AssertExpectations(s.T())
+}
+
+func (s *LockerTestSuite) TestUp_HeldLockContextDone() {
+	s.locker.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(ErrLockHeld)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := s.migrator.Up(ctx, "")
+	s.ErrorIs(err, ErrLockHeld)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.methods.AssertNotCalled(s.T(), "GetSchema", mock.Anything, mock.Anything)
+}
+
+func (s *LockerTestSuite) TestUp_AcquireLockError() {
+	errAcquire := fmt.Errorf("acquire failed")
+	s.locker.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(errAcquire).Once()
+
+	err := s.migrator.Up(context.Background(), "")
+	s.ErrorIs(err, errAcquire)
+	s.ErrorContains(err, "failed to acquire migration lock")
+	s.methods.AssertNotCalled(s.T(), "GetSchema", mock.Anything, mock.Anything)
+}
+
+func (s *LockerTestSuite) TestUp_ReleaseLockError() {
+	errRelease := fmt.Errorf("release failed")
+	s.locker.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	s.locker.On("ReleaseLock", mock.Anything, mock.Anything).Return(errRelease).Once()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: Applied}, nil).Once()
+
+	err := s.migrator.Up(context.Background(), "")
+	s.ErrorIs(err, errRelease)
+	s.ErrorContains(err, "failed to release migration lock")
+}
+
+func (s *LockerTestSuite) TestUp_RefreshesLockDuringRun() {
+	s.migrator.LockTTL = 15 * time.Millisecond
+	s.locker.On("AcquireLock", mock.Anything, mock.Anything, s.migrator.LockTTL).Return(nil).Once()
+	s.locker.On("RefreshLock", mock.Anything, "test-owner", s.migrator.LockTTL).Return(nil)
+	s.locker.On("ReleaseLock", mock.Anything, mock.Anything).Return(nil).Once()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Once()
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{}, nil).Once()
+	s.methods.On("ApplyMigration", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		time.Sleep(50 * time.Millisecond)
+	}).Return(nil).Once()
+
+	err := s.migrator.Up(context.Background(), "")
+	s.NoError(err)
+	s.locker.AssertCalled(s.T(), "RefreshLock", mock.Anything, "test-owner", s.migrator.LockTTL)
+}
+
+func (s *LockerTestSuite) TestUp_LockLostCancelsRun() {
+	s.migrator.LockTTL = 15 * time.Millisecond
+	s.locker.On("AcquireLock", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	s.locker.On("RefreshLock", mock.Anything, mock.Anything, mock.Anything).Return(ErrLockLost).Once()
+	s.locker.On("ReleaseLock", mock.Anything, mock.Anything).Return(nil).Once()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Once()
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{}, nil).Once()
+	s.methods.On("ApplyMigration", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ctx, _ := args.Get(0).(context.Context)
+		<-ctx.Done()
+	}).Return(context.Canceled).Once()
+
+	err := s.migrator.Up(context.Background(), "")
+	s.ErrorIs(err, ErrLockLost)
+	s.ErrorIs(err, context.Canceled)
+}
+
+func (s *LockerTestSuite) TestLockStatus() {
+	expected := &Lock{Owner: "other-owner", ExpiresAt: time.Now().Add(time.Minute)}
+	s.locker.On("GetLock", mock.Anything).Return(expected, nil).Once()
+
+	lock, err := s.migrator.LockStatus(context.Background())
+	s.NoError(err)
+	s.Equal(expected, lock)
+	s.False(lock.IsExpired())
+}
+
+func (s *LockerTestSuite) TestForceUnlock() {
+	s.locker.On("ForceReleaseLock", mock.Anything).Return(nil).Once()
+
+	err := s.migrator.ForceUnlock(context.Background())
+	s.NoError(err)
+	s.locker.AssertExpectations(s.T())
+}
+
+func (s *LockerTestSuite) TestWithoutLocker() {
+	s.migrator.Locker = nil
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: Applied}, nil).Once()
+
+	s.NoError(s.migrator.Up(context.Background(), ""))
+	_, err := s.migrator.LockStatus(context.Background())
+	s.ErrorIs(err, ErrLockUnsupported)
+	s.ErrorIs(s.migrator.ForceUnlock(context.Background()), ErrLockUnsupported)
+}
+
+func TestLockerTestSuite(t *testing.T) {
+	suite.Run(t, new(LockerTestSuite))
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+)
+
+// loggerKey is the context key of the migration-scoped logger.
+type loggerKey struct{}
+
+// logger returns the logger of the migrator, the default logger when none is set.
+func (b *BaseMigrator) logger() *slog.Logger {
+	if b.Logger != nil {
+		return b.Logger
+	}
+	return slog.Default()
+}
+
+// withLogger returns a copy of the context carrying a logger.
+func withLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext returns the logger of the migration run by the context, with its version and direction.
+// It is the default logger outside of a migration.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
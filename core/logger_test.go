@@ -0,0 +1,110 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LoggerTestSuite struct {
+	suite.Suite
+	store    *fakeStore
+	migrator *BaseMigrator
+	logs     bytes.Buffer
+}
+
+func (s *LoggerTestSuite) SetupTest() {
+	s.store = &fakeStore{schemas: map[string]Schema{}}
+	s.logs.Reset()
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.store,
+		Migrations: []Migration{
+			{Version: "20240101_initial", Up: noopMutation, Down: noopMutation},
+		},
+		Logger: slog.New(slog.NewJSONHandler(&s.logs, nil)),
+	}
+}
+
+// records decodes the JSON records of the logs.
+func (s *LoggerTestSuite) records() []map[string]any {
+	records := []map[string]any{}
+	decoder := json.NewDecoder(&s.logs)
+	for decoder.More() {
+		record := map[string]any{}
+		s.Require().NoError(decoder.Decode(&record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func (s *LoggerTestSuite) TestUp_Records() {
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+
+	records := s.records()
+	s.Require().Len(records, 4)
+	s.Equal("run started", records[0]["msg"])
+	s.Equal("applying migration", records[1]["msg"])
+	s.Equal("in_progress", records[1]["status"])
+	s.Equal("migration applied", records[2]["msg"])
+	s.Equal("20240101_initial", records[2]["version"])
+	s.Equal("up", records[2]["direction"])
+	s.Equal("applied", records[2]["status"])
+	s.Contains(records[2], "duration")
+	s.Equal("run finished", records[3]["msg"])
+}
+
+func (s *LoggerTestSuite) TestDown_Records() {
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	s.logs.Reset()
+
+	s.Require().NoError(s.migrator.Down(context.Background(), "20240101_initial"))
+	records := s.records()
+	s.Require().Len(records, 4)
+	s.Equal("migration reverted", records[2]["msg"])
+	s.Equal("down", records[2]["direction"])
+	s.Equal("pending", records[2]["status"])
+}
+
+func (s *LoggerTestSuite) TestUp_FailedRecords() {
+	s.store.failWith = fmt.Errorf("apply failed")
+
+	s.Error(s.migrator.Up(context.Background(), ""))
+	records := s.records()
+	s.Require().Len(records, 4)
+	s.Equal("ERROR", records[2]["level"])
+	s.Equal("migration failed", records[2]["msg"])
+	s.Contains(records[2]["error"], "apply failed")
+	s.Equal("ERROR", records[3]["level"])
+	s.Equal("run failed", records[3]["msg"])
+}
+
+func (s *LoggerTestSuite) TestLoggerFromContext_InMigration() {
+	s.migrator.Migrations[0].BeforeUp = func(ctx context.Context) error {
+		LoggerFromContext(ctx).Info("taking a backup")
+		return nil
+	}
+
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	var found bool
+	for _, record := range s.records() {
+		if record["msg"] == "taking a backup" {
+			found = true
+			s.Equal("20240101_initial", record["version"])
+			s.Equal("up", record["direction"])
+		}
+	}
+	s.True(found)
+}
+
+func (s *LoggerTestSuite) TestLoggerFromContext_Default() {
+	s.Equal(slog.Default(), LoggerFromContext(context.Background()))
+}
+
+func TestLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggerTestSuite))
+}
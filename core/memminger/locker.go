@@ -0,0 +1,63 @@
+package memminger
+
+import (
+	"context"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+var _ core.Locker = (*Memminger)(nil)
+
+// AcquireLock implements core.Locker.
+// The lock is taken when it is free, expired or already owned by the owner, as with the lease based plugins.
+func (m *Memminger) AcquireLock(ctx context.Context, owner string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lock != nil && m.lock.Owner != owner && !m.lock.IsExpired() {
+		return core.ErrLockHeld
+	}
+	now := time.Now()
+	m.lock = &core.Lock{Owner: owner, AcquiredAt: now, ExpiresAt: now.Add(ttl)}
+	return nil
+}
+
+// RefreshLock implements core.Locker.
+func (m *Memminger) RefreshLock(ctx context.Context, owner string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lock == nil || m.lock.Owner != owner {
+		return core.ErrLockLost
+	}
+	m.lock.ExpiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+// ReleaseLock implements core.Locker.
+func (m *Memminger) ReleaseLock(ctx context.Context, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lock != nil && m.lock.Owner == owner {
+		m.lock = nil
+	}
+	return nil
+}
+
+// GetLock implements core.Locker.
+func (m *Memminger) GetLock(ctx context.Context) (*core.Lock, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lock == nil {
+		return nil, nil
+	}
+	lock := *m.lock
+	return &lock, nil
+}
+
+// ForceReleaseLock implements core.Locker.
+func (m *Memminger) ForceReleaseLock(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lock = nil
+	return nil
+}
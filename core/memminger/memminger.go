@@ -0,0 +1,269 @@
+// Package memminger provides an in-memory implementation of the Gomiger interface, for unit tests.
+// Extended from core.BaseMigrator, it exercises the ordering & failure handling of the migrations without any database.
+package memminger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+// ErrInjected is the error of the failures injected by FailOnApply.
+var ErrInjected = errors.New("injected failure")
+
+// errSchemaExists is returned when a version is applied twice.
+var errSchemaExists = errors.New("schema already exists")
+
+var _ core.BaseMigratorAbstractMethods = (*Memminger)(nil)
+
+// Memminger implements core.DbPlugin with an in-process schema store.
+// Its migrations are not transactional: a failed migration is marked as dirty, as with the non-transactional plugins.
+// It is its own core.Locker, so concurrent runs on a Memminger wait for each other.
+type Memminger struct {
+	*core.BaseMigrator
+
+	// FailOnApply fails the Nth call of ApplyMigration, counted from 1, as if the migration failed:
+	// its Up function is not run and it is marked as dirty. Zero disables it.
+	FailOnApply int
+	// FailOnSchemaUpdate is called before each write to the schema store, with the version & its new status.
+	// The status is empty when the schema is deleted. A non-nil error fails the write.
+	// It is called without holding the lock of the Memminger, so it may inspect it, e.g. with Schemas.
+	FailOnSchemaUpdate func(version string, status core.SchemaStatus) error
+
+	mu        sync.Mutex
+	connected bool
+	schemas   map[string]core.Schema
+	lock      *core.Lock
+	applies   int
+	applied   []string
+	reverted  []string
+}
+
+// NewMemminger creates a new Memminger plugin with an empty schema store.
+// The config is optional, only its OutOfOrder policy, AllowIrreversible option & Observers are used.
+func NewMemminger(cfg *core.GomigerConfig) *Memminger {
+	memminger := &Memminger{
+		BaseMigrator: &core.BaseMigrator{
+			Migrations: []core.Migration{},
+		},
+		schemas: map[string]core.Schema{},
+	}
+	if cfg != nil {
+		memminger.OutOfOrder, memminger.AllowIrreversible, memminger.Observers = cfg.OutOfOrder, cfg.AllowIrreversible, cfg.Observers
+	}
+	memminger.BaseMigratorAbstractMethods = memminger
+	memminger.Locker = memminger
+	return memminger
+}
+
+// Connect implements core.DbPlugin.
+func (m *Memminger) Connect(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connected = true
+	return nil
+}
+
+// GetSchema implements core.DbPlugin.
+func (m *Memminger) GetSchema(ctx context.Context, version string) (*core.Schema, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	schema, ok := m.schemas[version]
+	if !ok {
+		return nil, fmt.Errorf("failed to get schema at version: %s, Error: %w", version, core.ErrSchemaNotFound)
+	}
+	return &schema, nil
+}
+
+// ListSchemas implements core.DbPlugin.
+func (m *Memminger) ListSchemas(ctx context.Context) ([]core.Schema, error) {
+	return m.Schemas(), nil
+}
+
+// SaveSchema implements core.DbPlugin.
+func (m *Memminger) SaveSchema(ctx context.Context, schema core.Schema) error {
+	if err := m.writeSchema(schema); err != nil {
+		return fmt.Errorf("failed to save schema at version: %s, Error: %w", schema.Version, err)
+	}
+	return nil
+}
+
+// DeleteSchema implements core.DbPlugin.
+func (m *Memminger) DeleteSchema(ctx context.Context, version string) error {
+	if err := m.deleteSchema(version); err != nil {
+		return fmt.Errorf("failed to delete schema at version: %s, Error: %w", version, err)
+	}
+	return nil
+}
+
+// failOnSchemaUpdate calls FailOnSchemaUpdate, if any. The caller must not hold mu.
+func (m *Memminger) failOnSchemaUpdate(version string, status core.SchemaStatus) error {
+	if m.FailOnSchemaUpdate == nil {
+		return nil
+	}
+	return m.FailOnSchemaUpdate(version, status)
+}
+
+// writeSchema creates or replaces a schema, unless the write is failed by FailOnSchemaUpdate.
+func (m *Memminger) writeSchema(schema core.Schema) error {
+	if err := m.failOnSchemaUpdate(schema.Version, schema.Status); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schemas[schema.Version] = schema
+	return nil
+}
+
+// deleteSchema deletes a schema, unless the write is failed by FailOnSchemaUpdate.
+func (m *Memminger) deleteSchema(version string) error {
+	if err := m.failOnSchemaUpdate(version, ""); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.schemas, version)
+	return nil
+}
+
+// updateSchemaStatus records the status of a migration, stamped with its execution and the error of a failed one.
+func (m *Memminger) updateSchemaStatus(ctx context.Context, mi core.Migration, status core.SchemaStatus, duration time.Duration, cause error) error {
+	if err := m.failOnSchemaUpdate(mi.Version, status); err != nil {
+		return fmt.Errorf("failed to update schema status at version: %s to '%s', please recover it with the force or retry command, Error: %w", mi.Version, status, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	schema := m.schemas[mi.Version]
+	schema.Status, schema.Duration = status, duration
+	core.ExecutionFromContext(ctx).Stamp(&schema, cause)
+	m.schemas[mi.Version] = schema
+	return nil
+}
+
+// ApplyMigration implements core.DbPlugin.
+func (m *Memminger) ApplyMigration(ctx context.Context, mi core.Migration) error {
+	// Mark the migration as in progress (create a new schema).
+	startedAt := time.Now()
+	m.mu.Lock()
+	m.applies++
+	failed := m.applies == m.FailOnApply
+	_, exists := m.schemas[mi.Version]
+	m.mu.Unlock()
+	if exists {
+		return fmt.Errorf("failed to apply migration at version: %s, Error: %w", mi.Version, errSchemaExists)
+	}
+	schema := core.Schema{Version: mi.Version, Timestamp: startedAt, Status: core.InProgress, Checksum: mi.Checksum}
+	core.ExecutionFromContext(ctx).Stamp(&schema, nil)
+	err := m.writeSchema(schema)
+	if err != nil {
+		return fmt.Errorf("failed to apply migration at version: %s, Error: %w", mi.Version, err)
+	}
+	// Run the migration.
+	if failed {
+		err = ErrInjected
+	} else if mi.Up != nil {
+		err = mi.Up(ctx)
+	}
+	if err != nil {
+		// Mark the migration as dirty.
+		if err := m.updateSchemaStatus(ctx, mi, core.Dirty, 0, err); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to apply migration %s: %w", mi.Version, err)
+	}
+	// Mark the migration as applied.
+	if err := m.updateSchemaStatus(ctx, mi, core.Applied, time.Since(startedAt), nil); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.applied = append(m.applied, mi.Version)
+	m.mu.Unlock()
+	return nil
+}
+
+// RevertMigration implements core.DbPlugin.
+func (m *Memminger) RevertMigration(ctx context.Context, mi core.Migration) error {
+	if mi.Down != nil {
+		if err := mi.Down(ctx); err != nil {
+			// Mark the migration as dirty.
+			if err := m.updateSchemaStatus(ctx, mi, core.Dirty, 0, err); err != nil {
+				return err
+			}
+			return fmt.Errorf("failed to revert migration %s: %w", mi.Version, err)
+		}
+	}
+	// Delete the schema.
+	if err := m.DeleteSchema(ctx, mi.Version); err != nil {
+		return fmt.Errorf("%w, please recover it with 'force %s --status pending'", err, mi.Version)
+	}
+	m.mu.Lock()
+	m.reverted = append(m.reverted, mi.Version)
+	m.mu.Unlock()
+	return nil
+}
+
+// Connected reports whether Connect has been called.
+func (m *Memminger) Connected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.connected
+}
+
+// Schemas returns the schemas of the store, sorted by version.
+func (m *Memminger) Schemas() []core.Schema {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	schemas := make([]core.Schema, 0, len(m.schemas))
+	for _, schema := range m.schemas {
+		schemas = append(schemas, schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].Version < schemas[j].Version })
+	return schemas
+}
+
+// Applied returns the versions successfully applied, in the order they were applied.
+func (m *Memminger) Applied() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.applied...)
+}
+
+// Reverted returns the versions successfully reverted, in the order they were reverted.
+func (m *Memminger) Reverted() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.reverted...)
+}
+
+// Applies returns the number of ApplyMigration calls, failed ones included.
+func (m *Memminger) Applies() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.applies
+}
+
+// Seed writes schemas to the store as they are, e.g. to start a test from applied or dirty migrations.
+// The writes are not failed by FailOnSchemaUpdate.
+func (m *Memminger) Seed(schemas ...core.Schema) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, schema := range schemas {
+		m.schemas[schema.Version] = schema
+	}
+}
+
+// Clear empties the store and the history of the applied & reverted versions.
+// The migrations & the fault injection settings are kept.
+func (m *Memminger) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schemas = map[string]core.Schema{}
+	m.applies = 0
+	m.applied = nil
+	m.reverted = nil
+}
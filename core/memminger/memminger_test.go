@@ -0,0 +1,288 @@
+package memminger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/plugintest"
+	"github.com/stretchr/testify/suite"
+)
+
+type MemmingerTestSuite struct {
+	suite.Suite
+	memminger *Memminger
+	ctx       context.Context
+}
+
+// noop is the Down of the reversible test migrations.
+func noop(context.Context) error { return nil }
+
+func (s *MemmingerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.memminger = NewMemminger(&core.GomigerConfig{OutOfOrder: core.OutOfOrderAllow})
+	s.memminger.Migrations = []core.Migration{
+		{Version: "1.0.0", Down: noop},
+		{Version: "2.0.0", Down: noop},
+		{Version: "3.0.0", Down: noop},
+	}
+	s.Require().NoError(s.memminger.Connect(s.ctx))
+}
+
+func (s *MemmingerTestSuite) versionsOf(schemas []core.Schema) []string {
+	versions := []string{}
+	for _, schema := range schemas {
+		versions = append(versions, schema.Version)
+	}
+	return versions
+}
+
+func (s *MemmingerTestSuite) TestNewMemminger_NilConfig() {
+	memminger := NewMemminger(nil)
+	s.Require().False(memminger.Connected())
+	s.Require().NoError(memminger.Connect(s.ctx))
+	s.Require().True(memminger.Connected())
+	s.Require().NoError(memminger.Up(s.ctx, ""))
+}
+
+func (s *MemmingerTestSuite) TestNewMemminger_ConfigObservers() {
+	events := []core.EventType{}
+	memminger := NewMemminger(&core.GomigerConfig{Observers: []core.Observer{
+		core.ObserverFunc(func(ctx context.Context, event core.Event) { events = append(events, event.Type) }),
+	}})
+	s.Require().NoError(memminger.Connect(s.ctx))
+	s.Require().NoError(memminger.Up(s.ctx, ""))
+	s.Require().Equal([]core.EventType{core.EventRunStarted, core.EventRunFinished}, events)
+}
+
+func (s *MemmingerTestSuite) TestUp_AppliesInOrder() {
+	s.memminger.Migrations[0].DependsOn = []string{"3.0.0"}
+	err := s.memminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"2.0.0", "3.0.0", "1.0.0"}, s.memminger.Applied())
+	s.Require().Equal([]string{"1.0.0", "2.0.0", "3.0.0"}, s.versionsOf(s.memminger.Schemas()))
+	for _, schema := range s.memminger.Schemas() {
+		s.Require().Equal(core.Applied, schema.Status)
+	}
+}
+
+func (s *MemmingerTestSuite) TestDown_RevertsInReverseOrder() {
+	s.Require().NoError(s.memminger.Up(s.ctx, ""))
+	err := s.memminger.Down(s.ctx, "2.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"3.0.0", "2.0.0"}, s.memminger.Reverted())
+	s.Require().Equal([]string{"1.0.0"}, s.versionsOf(s.memminger.Schemas()))
+}
+
+func (s *MemmingerTestSuite) TestApplyMigration_RunsUpAndRecordsSchema() {
+	ran := false
+	mi := core.Migration{
+		Version:  "1.0.0",
+		Up:       func(ctx context.Context) error { ran = true; return nil },
+		Checksum: "checksum-v1",
+	}
+	err := s.memminger.ApplyMigration(s.ctx, mi)
+	s.Require().NoError(err)
+	s.Require().True(ran)
+	schema, err := s.memminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+	s.Require().Equal("checksum-v1", schema.Checksum)
+}
+
+func (s *MemmingerTestSuite) TestApplyMigration_DuplicateVersion() {
+	s.memminger.Seed(core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	err := s.memminger.ApplyMigration(s.ctx, core.Migration{Version: "1.0.0"})
+	s.Require().Error(err)
+	s.ErrorContains(err, "failed to apply migration at version")
+	s.Require().Empty(s.memminger.Applied())
+}
+
+func (s *MemmingerTestSuite) TestApplyMigration_UpErrorMarksDirty() {
+	errUp := fmt.Errorf("migration failed")
+	err := s.memminger.ApplyMigration(s.ctx, core.Migration{
+		Version: "1.0.0",
+		Up:      func(ctx context.Context) error { return errUp },
+	})
+	s.Require().ErrorIs(err, errUp)
+	schema, err := s.memminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+}
+
+func (s *MemmingerTestSuite) TestUp_RecordsTheExecution() {
+	s.memminger.FailOnApply = 2
+	s.memminger.AppVersion = "v1.2.3"
+	s.Require().ErrorIs(s.memminger.Up(s.ctx, ""), ErrInjected)
+
+	applied, err := s.memminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Contains(applied.AppliedBy, "@")
+	s.Require().Equal("v1.2.3", applied.AppVersion)
+	s.Require().Equal(core.DirectionUp, applied.Direction)
+	s.Require().Empty(applied.Error)
+	dirty, err := s.memminger.GetSchema(s.ctx, "2.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, dirty.Status)
+	s.Require().Equal(ErrInjected.Error(), dirty.Error)
+}
+
+func (s *MemmingerTestSuite) TestFailOnApply() {
+	s.memminger.FailOnApply = 2
+	ran := []string{}
+	for i := range s.memminger.Migrations {
+		version := s.memminger.Migrations[i].Version
+		s.memminger.Migrations[i].Up = func(ctx context.Context) error {
+			ran = append(ran, version)
+			return nil
+		}
+	}
+	err := s.memminger.Up(s.ctx, "")
+	s.Require().ErrorIs(err, ErrInjected)
+	// The second migration is marked as dirty without running, the third one is not applied.
+	s.Require().Equal([]string{"1.0.0"}, ran)
+	s.Require().Equal([]string{"1.0.0"}, s.memminger.Applied())
+	s.Require().Equal(2, s.memminger.Applies())
+	schema, err := s.memminger.GetSchema(s.ctx, "2.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+	_, err = s.memminger.GetSchema(s.ctx, "3.0.0")
+	s.Require().ErrorIs(err, core.ErrSchemaNotFound)
+
+	// The retry is the third call, so it succeeds.
+	err = s.memminger.Retry(s.ctx, "2.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"1.0.0", "2.0.0"}, ran)
+}
+
+func (s *MemmingerTestSuite) TestUp_ConcurrentRunsWaitForTheLock() {
+	started, proceed := make(chan struct{}), make(chan struct{})
+	s.memminger.Migrations = []core.Migration{{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			close(started)
+			<-proceed
+			return nil
+		},
+	}}
+	first := make(chan error, 1)
+	go func() { first <- s.memminger.Up(s.ctx, "") }()
+	<-started
+	// A second run of the process waits for the lock of the first one.
+	ctx, cancel := context.WithTimeout(s.ctx, 50*time.Millisecond)
+	defer cancel()
+	s.Require().ErrorIs(s.memminger.Up(ctx, ""), core.ErrLockHeld)
+	close(proceed)
+	s.Require().NoError(<-first)
+	s.Require().Equal([]string{"1.0.0"}, s.memminger.Applied())
+	lock, err := s.memminger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(lock)
+}
+
+func (s *MemmingerTestSuite) TestFailOnSchemaUpdate_LeavesInProgress() {
+	errWrite := errors.New("write failed")
+	s.memminger.FailOnSchemaUpdate = func(version string, status core.SchemaStatus) error {
+		if version == "1.0.0" && status == core.Applied {
+			return errWrite
+		}
+		return nil
+	}
+	err := s.memminger.Up(s.ctx, "")
+	s.Require().ErrorIs(err, errWrite)
+	s.ErrorContains(err, "please recover it with the force or retry command")
+	// The migration has run, but it is still marked as in progress.
+	schema, err := s.memminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.InProgress, schema.Status)
+	s.Require().Empty(s.memminger.Applied())
+	// Repair marks the stuck migration as dirty.
+	s.memminger.FailOnSchemaUpdate = nil
+	repaired, err := s.memminger.Repair(s.ctx, 0)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"1.0.0"}, s.versionsOf(repaired))
+}
+
+func (s *MemmingerTestSuite) TestFailOnSchemaUpdate_Delete() {
+	s.Require().NoError(s.memminger.Up(s.ctx, ""))
+	errWrite := errors.New("write failed")
+	s.memminger.FailOnSchemaUpdate = func(version string, status core.SchemaStatus) error {
+		if status == "" {
+			return errWrite
+		}
+		return nil
+	}
+	err := s.memminger.Down(s.ctx, "3.0.0")
+	s.Require().ErrorIs(err, errWrite)
+	s.ErrorContains(err, "force 3.0.0 --status pending")
+	s.Require().Empty(s.memminger.Reverted())
+}
+
+func (s *MemmingerTestSuite) TestFailOnSchemaUpdate_InspectsTheStore() {
+	// The callback sees the store as it was before each write.
+	seen := []int{}
+	s.memminger.FailOnSchemaUpdate = func(version string, status core.SchemaStatus) error {
+		seen = append(seen, len(s.memminger.Schemas()))
+		return nil
+	}
+	s.Require().NoError(s.memminger.Up(s.ctx, "1.0.0"))
+	s.Require().NoError(s.memminger.Down(s.ctx, "1.0.0"))
+	s.Require().Equal([]int{0, 1, 1}, seen)
+}
+
+func (s *MemmingerTestSuite) TestRevertMigration_DownErrorMarksDirty() {
+	s.memminger.Seed(core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	errDown := fmt.Errorf("revert failed")
+	err := s.memminger.RevertMigration(s.ctx, core.Migration{
+		Version: "1.0.0",
+		Down:    func(ctx context.Context) error { return errDown },
+	})
+	s.Require().ErrorIs(err, errDown)
+	schema, err := s.memminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+}
+
+func (s *MemmingerTestSuite) TestSeed_DirtyBlocksUp() {
+	s.memminger.Seed(core.Schema{Version: "1.0.0", Status: core.Dirty, Timestamp: time.Now()})
+	err := s.memminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"2.0.0", "3.0.0"}, s.memminger.Applied())
+	statuses, err := s.memminger.Status(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(core.StateDirty, statuses[0].State)
+}
+
+func (s *MemmingerTestSuite) TestClear() {
+	s.memminger.FailOnApply = 3
+	s.Require().Error(s.memminger.Up(s.ctx, ""))
+	s.memminger.Clear()
+	s.Require().Empty(s.memminger.Schemas())
+	s.Require().Empty(s.memminger.Applied())
+	s.Require().Zero(s.memminger.Applies())
+	// The fault injection settings are kept.
+	s.Require().ErrorIs(s.memminger.Up(s.ctx, ""), ErrInjected)
+}
+
+func TestMemmingerTestSuite(t *testing.T) {
+	suite.Run(t, new(MemmingerTestSuite))
+}
+
+func TestMemmingerConformance(t *testing.T) {
+	// The plugins of a test share the store & the lock, as the migrators of a database.
+	plugins := map[*testing.T]*Memminger{}
+	plugintest.Run(t, func(t *testing.T) core.BaseMigratorAbstractMethods {
+		if memminger, ok := plugins[t]; ok {
+			return memminger
+		}
+		memminger := NewMemminger(nil)
+		if err := memminger.Connect(context.Background()); err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		plugins[t] = memminger
+		return memminger
+	})
+}
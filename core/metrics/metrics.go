@@ -0,0 +1,134 @@
+// Package metrics exposes Prometheus metrics of the migrations:
+// the applied, reverted & failed migrations, their duration, and the pending & dirty migrations.
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace is the prefix of the metric names.
+const Namespace = "gomiger"
+
+var (
+	_ core.Observer        = (*Collector)(nil)
+	_ prometheus.Collector = (*Collector)(nil)
+)
+
+// Collector is a core.Observer which counts the migrations of the runs, and a prometheus.Collector exposing them.
+// Register it in a registry to expose the metrics of a service, or write them to a file with WriteTextfile.
+type Collector struct {
+	applied  prometheus.Counter
+	reverted prometheus.Counter
+	failed   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	pending  prometheus.Gauge
+	dirty    prometheus.Gauge
+}
+
+// NewCollector creates a new Collector.
+func NewCollector() *Collector {
+	return &Collector{
+		applied: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "migrations_applied_total",
+			Help:      "Number of applied migrations.",
+		}),
+		reverted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "migrations_reverted_total",
+			Help:      "Number of reverted migrations.",
+		}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "migrations_failed_total",
+			Help:      "Number of migrations which failed to be applied or reverted.",
+		}, []string{"direction"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "migration_duration_seconds",
+			Help:      "Duration of the applied & reverted migrations.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 3600},
+		}, []string{"direction"}),
+		pending: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "migrations_pending",
+			Help:      "Number of pending migrations, as of the last refresh.",
+		}),
+		dirty: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "migrations_dirty",
+			Help:      "Number of dirty migrations, as of the last refresh.",
+		}),
+	}
+}
+
+// OnEvent implements core.Observer.
+func (c *Collector) OnEvent(_ context.Context, event core.Event) {
+	switch event.Type {
+	case core.EventMigrationApplied:
+		c.applied.Inc()
+		c.duration.WithLabelValues(string(event.Direction)).Observe(event.Duration.Seconds())
+	case core.EventMigrationReverted:
+		c.reverted.Inc()
+		c.duration.WithLabelValues(string(event.Direction)).Observe(event.Duration.Seconds())
+	case core.EventMigrationFailed:
+		c.failed.WithLabelValues(string(event.Direction)).Inc()
+	}
+}
+
+// Refresh sets the pending & dirty gauges from the status of the migrator,
+// e.g. after a run, or periodically in a service.
+func (c *Collector) Refresh(ctx context.Context, migrator core.Gomiger) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh the metrics: %w", err)
+	}
+	var pending, dirty int
+	for _, status := range statuses {
+		switch status.State {
+		case core.StatePending:
+			pending++
+		case core.StateDirty:
+			dirty++
+		}
+	}
+	c.pending.Set(float64(pending))
+	c.dirty.Set(float64(dirty))
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{c.applied, c.reverted, c.failed, c.duration, c.pending, c.dirty}
+}
+
+// WriteTextfile writes the metrics to a file in the Prometheus text format,
+// for the textfile collector of the node exporter when the migrations run as a job.
+// The file is replaced atomically.
+func (c *Collector) WriteTextfile(path string) error {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(c); err != nil {
+		return fmt.Errorf("failed to register the metrics: %w", err)
+	}
+	if err := prometheus.WriteToTextfile(path, registry); err != nil {
+		return fmt.Errorf("failed to write the metrics to %s: %w", path, err)
+	}
+	return nil
+}
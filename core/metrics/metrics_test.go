@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/memminger"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+type CollectorTestSuite struct {
+	suite.Suite
+	ctx       context.Context
+	collector *Collector
+	migrator  *memminger.Memminger
+}
+
+func (s *CollectorTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.collector = NewCollector()
+	s.migrator = memminger.NewMemminger(&core.GomigerConfig{Observers: []core.Observer{s.collector}})
+	noop := func(context.Context) error { return nil }
+	s.migrator.Migrations = []core.Migration{
+		{Version: "1.0.0", Down: noop},
+		{Version: "2.0.0", Down: noop},
+		{Version: "3.0.0", Down: noop},
+	}
+	s.Require().NoError(s.migrator.Connect(s.ctx))
+}
+
+func (s *CollectorTestSuite) TestOnEvent_Counters() {
+	s.Require().NoError(s.migrator.Up(s.ctx, "2.0.0"))
+	s.Require().NoError(s.migrator.Down(s.ctx, "2.0.0"))
+
+	s.Equal(2.0, testutil.ToFloat64(s.collector.applied))
+	s.Equal(1.0, testutil.ToFloat64(s.collector.reverted))
+	s.Equal(0, testutil.CollectAndCount(s.collector.failed))
+	s.Equal(2, testutil.CollectAndCount(s.collector.duration), "a histogram per direction")
+}
+
+func (s *CollectorTestSuite) TestOnEvent_Failed() {
+	s.migrator.FailOnApply = 2
+
+	s.Require().ErrorIs(s.migrator.Up(s.ctx, ""), memminger.ErrInjected)
+	s.Equal(1.0, testutil.ToFloat64(s.collector.applied))
+	s.Equal(1.0, testutil.ToFloat64(s.collector.failed.WithLabelValues("up")))
+}
+
+func (s *CollectorTestSuite) TestRefresh() {
+	s.migrator.FailOnApply = 2
+	s.Require().Error(s.migrator.Up(s.ctx, ""))
+
+	s.Require().NoError(s.collector.Refresh(s.ctx, s.migrator))
+	s.Equal(1.0, testutil.ToFloat64(s.collector.pending))
+	s.Equal(1.0, testutil.ToFloat64(s.collector.dirty))
+}
+
+// unavailable is a migrator whose schema store cannot be read.
+type unavailable struct {
+	core.Gomiger
+}
+
+func (unavailable) Status(context.Context) ([]core.MigrationStatus, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (s *CollectorTestSuite) TestRefresh_StatusError() {
+	s.ErrorContains(s.collector.Refresh(s.ctx, unavailable{}), "failed to refresh the metrics: connection refused")
+}
+
+func (s *CollectorTestSuite) TestWriteTextfile() {
+	s.Require().NoError(s.migrator.Up(s.ctx, ""))
+	s.Require().NoError(s.collector.Refresh(s.ctx, s.migrator))
+	path := filepath.Join(s.T().TempDir(), "gomiger.prom")
+
+	s.Require().NoError(s.collector.WriteTextfile(path))
+	content, err := os.ReadFile(path)
+	s.Require().NoError(err)
+	for _, expected := range []string{
+		"gomiger_migrations_applied_total 3",
+		"gomiger_migrations_pending 0",
+		"gomiger_migrations_dirty 0",
+		`gomiger_migration_duration_seconds_count{direction="up"} 3`,
+	} {
+		s.True(strings.Contains(string(content), expected), "missing %q in:\n%s", expected, content)
+	}
+}
+
+func TestCollectorTestSuite(t *testing.T) {
+	suite.Run(t, new(CollectorTestSuite))
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Redo reverts the last applied or dirty migration, then applies it again.
+func (b *BaseMigrator) Redo(ctx context.Context) (err error) {
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+	plan, err := b.plan(ctx, DirectionDown, "", 1)
+	if err != nil {
+		return err
+	}
+	runs := plan.Runs()
+	if len(runs) == 0 {
+		return fmt.Errorf("nothing to redo, no migration is applied")
+	}
+	if err := b.execute(ctx, plan); err != nil {
+		return err
+	}
+	return b.execute(ctx, &Plan{Direction: DirectionUp, Steps: runs})
+}
+
+// Reset reverts all the applied or dirty migrations.
+func (b *BaseMigrator) Reset(ctx context.Context) (err error) {
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+	plan, err := b.plan(ctx, DirectionDown, "", 0)
+	if err != nil {
+		return err
+	}
+	return b.execute(ctx, plan)
+}
+
+// Goto migrates the database to a version, in either direction:
+// the migrations after the version are reverted, then the pending migrations until the version are applied.
+func (b *BaseMigrator) Goto(ctx context.Context, version string) (err error) {
+	if !b.isVersionExists(version) {
+		return fmt.Errorf("version %s does not exist", version)
+	}
+	migrations, err := SortMigrations(b.Migrations)
+	if err != nil {
+		return err
+	}
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+	for i, mi := range migrations {
+		if mi.Version != version || i == len(migrations)-1 {
+			continue
+		}
+		// Revert down to the migration right after the version.
+		plan, err := b.plan(ctx, DirectionDown, migrations[i+1].Version, 0)
+		if err != nil {
+			return err
+		}
+		if err := b.execute(ctx, plan); err != nil {
+			return err
+		}
+	}
+	plan, err := b.plan(ctx, DirectionUp, version, 0)
+	if err != nil {
+		return err
+	}
+	return b.execute(ctx, plan)
+}
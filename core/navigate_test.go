@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeStore keeps the schemas in memory, and records the applied & reverted versions.
+type fakeStore struct {
+	schemas  map[string]Schema
+	history  []string
+	failWith error
+	// revertFailWith fails the reverts, leaving their schemas dirty.
+	revertFailWith error
+}
+
+func (f *fakeStore) Connect(ctx context.Context) error { return nil }
+
+func (f *fakeStore) GetSchema(ctx context.Context, version string) (*Schema, error) {
+	schema, ok := f.schemas[version]
+	if !ok {
+		return nil, ErrSchemaNotFound
+	}
+	return &schema, nil
+}
+
+func (f *fakeStore) ListSchemas(ctx context.Context) ([]Schema, error) {
+	schemas := []Schema{}
+	for _, schema := range f.schemas {
+		schemas = append(schemas, schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].Version < schemas[j].Version })
+	return schemas, nil
+}
+
+func (f *fakeStore) SaveSchema(ctx context.Context, schema Schema) error {
+	f.schemas[schema.Version] = schema
+	return nil
+}
+
+func (f *fakeStore) DeleteSchema(ctx context.Context, version string) error {
+	delete(f.schemas, version)
+	return nil
+}
+
+// write stamps a schema with the execution of the context, as the plugins do, then saves it.
+func (f *fakeStore) write(ctx context.Context, schema Schema, err error) {
+	ExecutionFromContext(ctx).Stamp(&schema, err)
+	f.schemas[schema.Version] = schema
+}
+
+func (f *fakeStore) ApplyMigration(ctx context.Context, mi Migration) error {
+	if f.failWith != nil {
+		f.write(ctx, Schema{Version: mi.Version, Status: Dirty}, f.failWith)
+		return f.failWith
+	}
+	f.write(ctx, Schema{Version: mi.Version, Status: Applied}, nil)
+	f.history = append(f.history, "up "+mi.Version)
+	return nil
+}
+
+func (f *fakeStore) RevertMigration(ctx context.Context, mi Migration) error {
+	if f.revertFailWith != nil {
+		f.write(ctx, Schema{Version: mi.Version, Status: Dirty}, f.revertFailWith)
+		return f.revertFailWith
+	}
+	if mi.Down != nil {
+		if err := mi.Down(ctx); err != nil {
+			f.write(ctx, Schema{Version: mi.Version, Status: Dirty}, err)
+			return err
+		}
+	}
+	delete(f.schemas, mi.Version)
+	f.history = append(f.history, "down "+mi.Version)
+	return nil
+}
+
+type NavigateTestSuite struct {
+	suite.Suite
+	store    *fakeStore
+	migrator *BaseMigrator
+}
+
+func (s *NavigateTestSuite) SetupTest() {
+	s.store = &fakeStore{schemas: map[string]Schema{}}
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.store,
+		Migrations: []Migration{
+			{Version: "20240101_initial", Down: noopMutation},
+			{Version: "20240201_add_users", Down: noopMutation},
+			{Version: "20240301_add_orders", Down: noopMutation},
+		},
+	}
+}
+
+// applied seeds the store with applied versions.
+func (s *NavigateTestSuite) applied(versions ...string) {
+	for _, version := range versions {
+		s.store.schemas[version] = Schema{Version: version, Status: Applied}
+	}
+}
+
+func (s *NavigateTestSuite) TestRedo() {
+	s.applied("20240101_initial", "20240201_add_users")
+
+	s.Require().NoError(s.migrator.Redo(context.Background()))
+	s.Equal([]string{"down 20240201_add_users", "up 20240201_add_users"}, s.store.history)
+}
+
+func (s *NavigateTestSuite) TestRedo_Dirty() {
+	s.applied("20240101_initial")
+	s.store.schemas["20240201_add_users"] = Schema{Version: "20240201_add_users", Status: Dirty}
+
+	s.Require().NoError(s.migrator.Redo(context.Background()))
+	s.Equal([]string{"down 20240201_add_users", "up 20240201_add_users"}, s.store.history)
+	s.Equal(Applied, s.store.schemas["20240201_add_users"].Status)
+}
+
+func (s *NavigateTestSuite) TestRedo_NothingApplied() {
+	err := s.migrator.Redo(context.Background())
+	s.ErrorContains(err, "nothing to redo")
+	s.Empty(s.store.history)
+}
+
+func (s *NavigateTestSuite) TestRedo_ApplyMigrationError() {
+	s.applied("20240101_initial")
+	errApply := fmt.Errorf("apply failed")
+	s.store.failWith = errApply
+
+	err := s.migrator.Redo(context.Background())
+	s.ErrorIs(err, errApply)
+	s.Equal([]string{"down 20240101_initial"}, s.store.history)
+}
+
+func (s *NavigateTestSuite) TestReset() {
+	s.applied("20240101_initial", "20240201_add_users", "20240301_add_orders")
+
+	s.Require().NoError(s.migrator.Reset(context.Background()))
+	s.Equal([]string{"down 20240301_add_orders", "down 20240201_add_users", "down 20240101_initial"}, s.store.history)
+	s.Empty(s.store.schemas)
+}
+
+func (s *NavigateTestSuite) TestReset_IrreversibleDownLeavesTheMigrationApplied() {
+	s.applied("20240101_initial", "20240201_add_users", "20240301_add_orders")
+	s.migrator.Migrations[1].Down = func(ctx context.Context) error { return ErrIrreversible }
+
+	err := s.migrator.Reset(context.Background())
+	s.ErrorIs(err, ErrIrreversible)
+	s.ErrorContains(err, "allow irreversible migrations")
+	s.Equal([]string{"down 20240301_add_orders"}, s.store.history)
+	s.Equal(Schema{Version: "20240201_add_users", Status: Applied}, s.store.schemas["20240201_add_users"])
+	s.Equal(Applied, s.store.schemas["20240101_initial"].Status)
+}
+
+func (s *NavigateTestSuite) TestGoto_NonexistentVersion() {
+	err := s.migrator.Goto(context.Background(), "20240401_nonexistent")
+	s.ErrorContains(err, "version 20240401_nonexistent does not exist")
+}
+
+func (s *NavigateTestSuite) TestGoto_Up() {
+	s.applied("20240101_initial")
+
+	s.Require().NoError(s.migrator.Goto(context.Background(), "20240201_add_users"))
+	s.Equal([]string{"up 20240201_add_users"}, s.store.history)
+}
+
+func (s *NavigateTestSuite) TestGoto_Down() {
+	s.applied("20240101_initial", "20240201_add_users", "20240301_add_orders")
+
+	s.Require().NoError(s.migrator.Goto(context.Background(), "20240101_initial"))
+	s.Equal([]string{"down 20240301_add_orders", "down 20240201_add_users"}, s.store.history)
+}
+
+func (s *NavigateTestSuite) TestGoto_BothDirections() {
+	// A migration merged late is pending below the target, while a newer one is applied.
+	s.applied("20240101_initial", "20240301_add_orders")
+
+	s.Require().NoError(s.migrator.Goto(context.Background(), "20240201_add_users"))
+	s.Equal([]string{"down 20240301_add_orders", "up 20240201_add_users"}, s.store.history)
+}
+
+func (s *NavigateTestSuite) TestGoto_Latest() {
+	s.Require().NoError(s.migrator.Goto(context.Background(), "20240301_add_orders"))
+	s.Equal([]string{"up 20240101_initial", "up 20240201_add_users", "up 20240301_add_orders"}, s.store.history)
+}
+
+func TestNavigateTestSuite(t *testing.T) {
+	suite.Run(t, new(NavigateTestSuite))
+}
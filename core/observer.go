@@ -0,0 +1,169 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EventType is the type of an event of a run.
+type EventType string
+
+var (
+	// EventRunStarted is emitted before the first migration of a run
+	EventRunStarted EventType = "run_started"
+	// EventMigrationStarted is emitted before a migration is applied or reverted
+	EventMigrationStarted EventType = "migration_started"
+	// EventMigrationApplied is emitted after a migration is applied
+	EventMigrationApplied EventType = "migration_applied"
+	// EventMigrationReverted is emitted after a migration is reverted
+	EventMigrationReverted EventType = "migration_reverted"
+	// EventMigrationFailed is emitted after a migration failed to be applied or reverted
+	EventMigrationFailed EventType = "migration_failed"
+	// EventRunFinished is emitted at the end of a run, successful or not
+	EventRunFinished EventType = "run_finished"
+)
+
+// Event is an event of a run. A run executes the migrations of a plan in a single direction:
+// Up & Down are a run, Redo & Goto are a down run followed by an up run.
+type Event struct {
+	Type      EventType `json:"type"`
+	Direction Direction `json:"direction"`
+	// Version is the version of the migration, empty for the run events.
+	Version string `json:"version,omitempty"`
+	// Duration is the duration of the migration or of the run, zero for the started events.
+	Duration time.Duration `json:"duration,omitempty"`
+	// Err is the error of a failed migration or run.
+	Err error `json:"-"`
+}
+
+// Observer is notified of the events of the runs, e.g. to send notifications or take a backup.
+// It is called synchronously, while the run is in progress.
+type Observer interface {
+	OnEvent(ctx context.Context, event Event)
+}
+
+// ObserverFunc is an Observer function.
+type ObserverFunc func(ctx context.Context, event Event)
+
+// OnEvent implements Observer.
+func (f ObserverFunc) OnEvent(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+// ContextObserver is an Observer which derives the context of a run or of a migration when it starts,
+// e.g. to start a tracing span. OnStart is called instead of OnEvent for the started events;
+// the derived context is passed to the migration, to its hooks and to the following events.
+type ContextObserver interface {
+	Observer
+	OnStart(ctx context.Context, event Event) context.Context
+}
+
+// emit notifies the observers of an event.
+func (b *BaseMigrator) emit(ctx context.Context, event Event) {
+	for _, observer := range b.Observers {
+		observer.OnEvent(ctx, event)
+	}
+}
+
+// start notifies the observers of a started event, and returns the context derived by the context observers.
+func (b *BaseMigrator) start(ctx context.Context, event Event) context.Context {
+	for _, observer := range b.Observers {
+		if observer, ok := observer.(ContextObserver); ok {
+			ctx = observer.OnStart(ctx, event)
+			continue
+		}
+		observer.OnEvent(ctx, event)
+	}
+	return ctx
+}
+
+// execute runs the migrations of a plan in order, and emits the events of the run.
+func (b *BaseMigrator) execute(ctx context.Context, plan *Plan) (err error) {
+	startedAt := time.Now()
+	logger := b.logger().With("direction", plan.Direction)
+	logger.Info("run started", "migrations", len(plan.Runs()))
+	ctx = b.start(ctx, Event{Type: EventRunStarted, Direction: plan.Direction})
+	defer func() {
+		if err != nil {
+			logger.Error("run failed", "duration", time.Since(startedAt), "error", err)
+		} else {
+			logger.Info("run finished", "duration", time.Since(startedAt))
+		}
+		b.emit(ctx, Event{Type: EventRunFinished, Direction: plan.Direction, Duration: time.Since(startedAt), Err: err})
+	}()
+	if plan.Direction == DirectionUp {
+		if err := b.checkOutOfOrder(plan); err != nil {
+			return err
+		}
+	}
+	for _, step := range plan.Runs() {
+		if plan.Direction == DirectionUp {
+			err = b.applyStep(ctx, step.Migration)
+		} else {
+			err = b.revertStep(ctx, step.Migration, step.schema)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyStep applies a migration between its hooks, and emits its events.
+// The hooks & the migration get the migration-scoped logger in their context, and the migration its execution.
+func (b *BaseMigrator) applyStep(ctx context.Context, mi Migration) error {
+	startedAt := time.Now()
+	logger := b.logger().With("version", mi.Version, "direction", DirectionUp)
+	ctx = withLogger(ctx, logger)
+	fail := func(err error) error {
+		logger.Error("migration failed", "duration", time.Since(startedAt), "error", err)
+		b.emit(ctx, Event{Type: EventMigrationFailed, Direction: DirectionUp, Version: mi.Version, Duration: time.Since(startedAt), Err: err})
+		return err
+	}
+	logger.Info("applying migration", "status", InProgress)
+	ctx = b.start(ctx, Event{Type: EventMigrationStarted, Direction: DirectionUp, Version: mi.Version})
+	if mi.BeforeUp != nil {
+		if err := mi.BeforeUp(ctx); err != nil {
+			return fail(fmt.Errorf("failed to run the before up hook of migration %s: %w", mi.Version, err))
+		}
+	}
+	if err := b.ApplyMigration(withExecution(ctx, b.execution(DirectionUp)), mi); err != nil {
+		return fail(fmt.Errorf("failed to apply migration %s: %w", mi.Version, err))
+	}
+	logger.Info("migration applied", "status", Applied, "duration", time.Since(startedAt))
+	b.emit(ctx, Event{Type: EventMigrationApplied, Direction: DirectionUp, Version: mi.Version, Duration: time.Since(startedAt)})
+	if mi.AfterUp != nil {
+		if err := mi.AfterUp(ctx); err != nil {
+			return fmt.Errorf("failed to run the after up hook of migration %s, the migration is applied: %w", mi.Version, err)
+		}
+	}
+	return nil
+}
+
+// revertStep reverts a migration, and emits its events.
+// The migration gets the migration-scoped logger & its execution in its context.
+// A Down failing with ErrIrreversible leaves the migration as it was: its schema is restored if the plugin marked it as dirty.
+func (b *BaseMigrator) revertStep(ctx context.Context, mi Migration, schema *Schema) error {
+	startedAt := time.Now()
+	logger := b.logger().With("version", mi.Version, "direction", DirectionDown)
+	ctx = withLogger(ctx, logger)
+	logger.Info("reverting migration")
+	ctx = b.start(ctx, Event{Type: EventMigrationStarted, Direction: DirectionDown, Version: mi.Version})
+	if err := b.RevertMigration(withExecution(ctx, b.execution(DirectionDown)), b.revertible(mi)); err != nil {
+		if errors.Is(err, ErrIrreversible) && schema != nil {
+			err = fmt.Errorf("%w, it is left as is, allow irreversible migrations to forget it without reverting it", err)
+			if restoreErr := b.SaveSchema(context.WithoutCancel(ctx), *schema); restoreErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to restore schema at version %s: %w", mi.Version, restoreErr))
+			}
+		}
+		err = fmt.Errorf("failed to revert migration %s: %w", mi.Version, err)
+		logger.Error("migration failed", "duration", time.Since(startedAt), "error", err)
+		b.emit(ctx, Event{Type: EventMigrationFailed, Direction: DirectionDown, Version: mi.Version, Duration: time.Since(startedAt), Err: err})
+		return err
+	}
+	logger.Info("migration reverted", "status", StatePending, "duration", time.Since(startedAt))
+	b.emit(ctx, Event{Type: EventMigrationReverted, Direction: DirectionDown, Version: mi.Version, Duration: time.Since(startedAt)})
+	return nil
+}
@@ -0,0 +1,213 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ObserverTestSuite struct {
+	suite.Suite
+	store    *fakeStore
+	migrator *BaseMigrator
+	events   []Event
+}
+
+func (s *ObserverTestSuite) SetupTest() {
+	s.store = &fakeStore{schemas: map[string]Schema{}}
+	s.events = nil
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.store,
+		Migrations: []Migration{
+			{Version: "20240101_initial", Down: noopMutation},
+			{Version: "20240201_add_users", Down: noopMutation},
+		},
+		Observers: []Observer{ObserverFunc(func(ctx context.Context, event Event) {
+			s.events = append(s.events, event)
+		})},
+	}
+}
+
+// types returns the types of the received events, with their version.
+func (s *ObserverTestSuite) types() []string {
+	types := []string{}
+	for _, event := range s.events {
+		types = append(types, fmt.Sprintf("%s %s", event.Type, event.Version))
+	}
+	return types
+}
+
+func (s *ObserverTestSuite) TestUp_Events() {
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	s.Equal([]string{
+		"run_started ",
+		"migration_started 20240101_initial",
+		"migration_applied 20240101_initial",
+		"migration_started 20240201_add_users",
+		"migration_applied 20240201_add_users",
+		"run_finished ",
+	}, s.types())
+	for _, event := range s.events {
+		s.Equal(DirectionUp, event.Direction)
+		s.NoError(event.Err)
+	}
+}
+
+func (s *ObserverTestSuite) TestDown_Events() {
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	s.events = nil
+
+	s.Require().NoError(s.migrator.Down(context.Background(), "20240201_add_users"))
+	s.Equal([]string{
+		"run_started ",
+		"migration_started 20240201_add_users",
+		"migration_reverted 20240201_add_users",
+		"run_finished ",
+	}, s.types())
+	for _, event := range s.events {
+		s.Equal(DirectionDown, event.Direction)
+	}
+}
+
+func (s *ObserverTestSuite) TestUp_FailedEvents() {
+	errApply := fmt.Errorf("apply failed")
+	s.store.failWith = errApply
+
+	err := s.migrator.Up(context.Background(), "")
+	s.ErrorIs(err, errApply)
+	s.Equal([]string{
+		"run_started ",
+		"migration_started 20240101_initial",
+		"migration_failed 20240101_initial",
+		"run_finished ",
+	}, s.types())
+	s.ErrorIs(s.events[2].Err, errApply)
+	s.ErrorIs(s.events[3].Err, errApply)
+}
+
+func (s *ObserverTestSuite) TestRedo_Events() {
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	s.events = nil
+
+	s.Require().NoError(s.migrator.Redo(context.Background()))
+	s.Equal([]string{
+		"run_started ",
+		"migration_started 20240201_add_users",
+		"migration_reverted 20240201_add_users",
+		"run_finished ",
+		"run_started ",
+		"migration_started 20240201_add_users",
+		"migration_applied 20240201_add_users",
+		"run_finished ",
+	}, s.types())
+}
+
+func (s *ObserverTestSuite) TestHooks() {
+	calls := []string{}
+	s.migrator.Migrations[0].BeforeUp = func(ctx context.Context) error {
+		calls = append(calls, fmt.Sprintf("before, %d applied", len(s.store.schemas)))
+		return nil
+	}
+	s.migrator.Migrations[0].AfterUp = func(ctx context.Context) error {
+		calls = append(calls, fmt.Sprintf("after, %d applied", len(s.store.schemas)))
+		return nil
+	}
+
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	s.Equal([]string{"before, 0 applied", "after, 1 applied"}, calls)
+}
+
+func (s *ObserverTestSuite) TestBeforeUp_ErrorCancelsTheMigration() {
+	errHook := fmt.Errorf("backup failed")
+	s.migrator.Migrations[1].BeforeUp = func(ctx context.Context) error { return errHook }
+
+	err := s.migrator.Up(context.Background(), "")
+	s.ErrorIs(err, errHook)
+	s.ErrorContains(err, "before up hook of migration 20240201_add_users")
+	s.Equal([]string{"up 20240101_initial"}, s.store.history)
+	s.NotContains(s.store.schemas, "20240201_add_users")
+	s.Equal("migration_failed 20240201_add_users", s.types()[len(s.events)-2])
+}
+
+func (s *ObserverTestSuite) TestAfterUp_ErrorKeepsTheMigration() {
+	errHook := fmt.Errorf("cache warm-up failed")
+	s.migrator.Migrations[0].AfterUp = func(ctx context.Context) error { return errHook }
+
+	err := s.migrator.Up(context.Background(), "")
+	s.ErrorIs(err, errHook)
+	s.Equal([]string{"up 20240101_initial"}, s.store.history)
+	s.Equal(Applied, s.store.schemas["20240101_initial"].Status)
+	s.ErrorIs(s.events[len(s.events)-1].Err, errHook)
+}
+
+func (s *ObserverTestSuite) TestRetry_HooksAndEvents() {
+	s.store.schemas["20240101_initial"] = Schema{Version: "20240101_initial", Status: Dirty}
+	calls := []string{}
+	s.migrator.Migrations[0].BeforeUp = func(ctx context.Context) error {
+		calls = append(calls, "before")
+		return nil
+	}
+	s.migrator.Migrations[0].AfterUp = func(ctx context.Context) error {
+		calls = append(calls, "after")
+		return nil
+	}
+
+	s.Require().NoError(s.migrator.Retry(context.Background(), "20240101_initial"))
+	s.Equal([]string{"before", "after"}, calls)
+	s.Equal([]string{
+		"migration_started 20240101_initial",
+		"migration_applied 20240101_initial",
+	}, s.types())
+}
+
+func (s *ObserverTestSuite) TestRetry_BeforeUpErrorKeepsTheMigrationDirty() {
+	s.store.schemas["20240101_initial"] = Schema{Version: "20240101_initial", Status: Dirty, Checksum: "sha256:initial"}
+	errHook := fmt.Errorf("backup failed")
+	s.migrator.Migrations[0].BeforeUp = func(ctx context.Context) error { return errHook }
+
+	err := s.migrator.Retry(context.Background(), "20240101_initial")
+	s.ErrorIs(err, errHook)
+	s.Empty(s.store.history)
+	s.Equal(Schema{Version: "20240101_initial", Status: Dirty, Checksum: "sha256:initial"}, s.store.schemas["20240101_initial"])
+	s.Equal("migration_failed 20240101_initial", s.types()[len(s.events)-1])
+}
+
+// versionKey is the context key set by contextObserver.
+type versionKey struct{}
+
+// contextObserver carries the version of the started migration in the context.
+type contextObserver struct {
+	finished []string
+}
+
+func (o *contextObserver) OnStart(ctx context.Context, event Event) context.Context {
+	return context.WithValue(ctx, versionKey{}, "started "+event.Version)
+}
+
+func (o *contextObserver) OnEvent(ctx context.Context, event Event) {
+	version, _ := ctx.Value(versionKey{}).(string)
+	o.finished = append(o.finished, fmt.Sprintf("%s in %s", event.Type, version))
+}
+
+func (s *ObserverTestSuite) TestContextObserver() {
+	observer := &contextObserver{}
+	s.migrator.Observers = append(s.migrator.Observers, observer)
+	s.migrator.Migrations = s.migrator.Migrations[:1]
+	var hookVersion any
+	s.migrator.Migrations[0].BeforeUp = func(ctx context.Context) error {
+		hookVersion = ctx.Value(versionKey{})
+		return nil
+	}
+
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	s.Equal("started 20240101_initial", hookVersion)
+	s.Equal([]string{"migration_applied in started 20240101_initial", "run_finished in started "}, observer.finished)
+	// The other observers still get the started events.
+	s.Equal("run_started ", s.types()[0])
+}
+
+func TestObserverTestSuite(t *testing.T) {
+	suite.Run(t, new(ObserverTestSuite))
+}
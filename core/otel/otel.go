@@ -0,0 +1,83 @@
+// Package otel traces the migration runs with OpenTelemetry.
+// Each run is a span, with a child span per applied or reverted migration.
+// The span of a migration is in the context passed to its Up & Down functions,
+// so the spans of an instrumented database driver nest under it.
+package otel
+
+import (
+	"context"
+
+	"github.com/ParteeLabs/gomiger/core"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer.
+const ScopeName = "github.com/ParteeLabs/gomiger/core/otel"
+
+var (
+	// VersionKey is the attribute of the version of a migration
+	VersionKey = attribute.Key("gomiger.version")
+	// DirectionKey is the attribute of the direction of a run or a migration: up or down
+	DirectionKey = attribute.Key("gomiger.direction")
+	// StatusKey is the attribute of the status of a migration after its span: applied, pending or failed
+	StatusKey = attribute.Key("gomiger.status")
+)
+
+var _ core.ContextObserver = (*Tracer)(nil)
+
+// Tracer is a core.ContextObserver which traces the runs & the migrations of a migrator.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer creates a new Tracer. The provider is optional, the global tracer provider is used by default.
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otelapi.GetTracerProvider()
+	}
+	return &Tracer{tracer: provider.Tracer(ScopeName)}
+}
+
+// OnStart implements core.ContextObserver, it starts the span of a run or of a migration.
+func (t *Tracer) OnStart(ctx context.Context, event core.Event) context.Context {
+	switch event.Type {
+	case core.EventRunStarted:
+		ctx, _ = t.tracer.Start(ctx, "gomiger "+string(event.Direction),
+			trace.WithAttributes(DirectionKey.String(string(event.Direction))),
+		)
+	case core.EventMigrationStarted:
+		name := "gomiger apply"
+		if event.Direction == core.DirectionDown {
+			name = "gomiger revert"
+		}
+		ctx, _ = t.tracer.Start(ctx, name, trace.WithAttributes(
+			VersionKey.String(event.Version),
+			DirectionKey.String(string(event.Direction)),
+		))
+	}
+	return ctx
+}
+
+// OnEvent implements core.Observer, it ends the span of a run or of a migration.
+func (t *Tracer) OnEvent(ctx context.Context, event core.Event) {
+	span := trace.SpanFromContext(ctx)
+	switch event.Type {
+	case core.EventMigrationApplied:
+		span.SetAttributes(StatusKey.String(string(core.Applied)))
+	case core.EventMigrationReverted:
+		span.SetAttributes(StatusKey.String(string(core.StatePending)))
+	case core.EventMigrationFailed:
+		span.SetAttributes(StatusKey.String("failed"))
+	case core.EventRunFinished:
+	default:
+		return
+	}
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,110 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/memminger"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TracerTestSuite struct {
+	suite.Suite
+	exporter *tracetest.InMemoryExporter
+	migrator *memminger.Memminger
+	// spanOf records the span in the context of each migration.
+	spanOf map[string]trace.SpanContext
+}
+
+func (s *TracerTestSuite) SetupTest() {
+	s.exporter = tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(s.exporter))
+	s.spanOf = map[string]trace.SpanContext{}
+	s.migrator = memminger.NewMemminger(nil)
+	for _, version := range []string{"20240101_initial", "20240201_add_users"} {
+		s.migrator.Migrations = append(s.migrator.Migrations, core.Migration{
+			Version: version,
+			Up: func(ctx context.Context) error {
+				s.spanOf[version] = trace.SpanContextFromContext(ctx)
+				return nil
+			},
+			Down: func(ctx context.Context) error { return nil },
+		})
+	}
+	s.migrator.Observers = append(s.migrator.Observers, NewTracer(provider))
+}
+
+// attributesOf returns the attributes of a span, keyed by name.
+func attributesOf(span tracetest.SpanStub) map[attribute.Key]string {
+	attributes := map[attribute.Key]string{}
+	for _, kv := range span.Attributes {
+		attributes[kv.Key] = kv.Value.Emit()
+	}
+	return attributes
+}
+
+func (s *TracerTestSuite) TestUp_Spans() {
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+
+	spans := s.exporter.GetSpans()
+	s.Require().Len(spans, 3)
+	// The spans are exported when they end: the migrations, then the run.
+	run := spans[2]
+	s.Equal("gomiger up", run.Name)
+	s.Equal("up", attributesOf(run)[DirectionKey])
+	for i, version := range []string{"20240101_initial", "20240201_add_users"} {
+		span := spans[i]
+		s.Equal("gomiger apply", span.Name)
+		s.Equal(run.SpanContext.SpanID(), span.Parent.SpanID())
+		s.Equal(map[attribute.Key]string{VersionKey: version, DirectionKey: "up", StatusKey: "applied"}, attributesOf(span))
+		// The span is propagated to the Up function.
+		s.Equal(span.SpanContext.SpanID(), s.spanOf[version].SpanID())
+	}
+}
+
+func (s *TracerTestSuite) TestDown_Spans() {
+	s.Require().NoError(s.migrator.Up(context.Background(), ""))
+	s.exporter.Reset()
+
+	s.Require().NoError(s.migrator.Down(context.Background(), "20240201_add_users"))
+	spans := s.exporter.GetSpans()
+	s.Require().Len(spans, 2)
+	s.Equal("gomiger revert", spans[0].Name)
+	s.Equal(map[attribute.Key]string{VersionKey: "20240201_add_users", DirectionKey: "down", StatusKey: "pending"}, attributesOf(spans[0]))
+	s.Equal("gomiger down", spans[1].Name)
+}
+
+func (s *TracerTestSuite) TestUp_FailedSpans() {
+	s.migrator.FailOnApply = 2
+
+	s.Require().ErrorIs(s.migrator.Up(context.Background(), ""), memminger.ErrInjected)
+	spans := s.exporter.GetSpans()
+	s.Require().Len(spans, 3)
+	s.Equal(codes.Unset, spans[0].Status.Code)
+	s.Equal("failed", attributesOf(spans[1])[StatusKey])
+	s.Equal(codes.Error, spans[1].Status.Code)
+	s.Len(spans[1].Events, 1, "the error is recorded")
+	s.Equal(codes.Error, spans[2].Status.Code)
+}
+
+func (s *TracerTestSuite) TestUp_NestsUnderTheCallerSpan() {
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(s.exporter))
+	ctx, deploy := provider.Tracer("deploy").Start(context.Background(), "deploy")
+
+	s.Require().NoError(s.migrator.Up(ctx, ""))
+	deploy.End()
+	spans := s.exporter.GetSpans()
+	s.Require().Len(spans, 4)
+	s.Equal(deploy.SpanContext().SpanID(), spans[2].Parent.SpanID(), fmt.Sprintf("%s is not under the deploy span", spans[2].Name))
+}
+
+func TestTracerTestSuite(t *testing.T) {
+	suite.Run(t, new(TracerTestSuite))
+}
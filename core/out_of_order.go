@@ -0,0 +1,76 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrOutOfOrder is returned by Up, under the strict policy, when pending migrations sort before the latest applied version.
+var ErrOutOfOrder = errors.New("out of order migrations")
+
+// OutOfOrderPolicy is what Up does with pending migrations which sort before the latest applied version,
+// e.g. a migration of a feature branch merged after newer migrations were applied.
+type OutOfOrderPolicy string
+
+var (
+	// OutOfOrderStrict fails the run before executing any migration
+	OutOfOrderStrict OutOfOrderPolicy = "strict"
+	// OutOfOrderWarn logs a warning, then applies the migrations
+	OutOfOrderWarn OutOfOrderPolicy = "warn"
+	// OutOfOrderAllow applies the migrations silently
+	OutOfOrderAllow OutOfOrderPolicy = "allow"
+)
+
+// Validate checks that the policy is known. The empty policy is allowed, and defaults to OutOfOrderWarn.
+func (p OutOfOrderPolicy) Validate() error {
+	switch p {
+	case "", OutOfOrderStrict, OutOfOrderWarn, OutOfOrderAllow:
+		return nil
+	default:
+		return fmt.Errorf("unknown out of order policy %s, only %s, %s and %s are allowed", p, OutOfOrderStrict, OutOfOrderWarn, OutOfOrderAllow)
+	}
+}
+
+// outOfOrder lists the versions which the plan runs, and which sort before the latest applied version.
+func (b *BaseMigrator) outOfOrder(ctx context.Context, plan *Plan) (latest string, versions []string, err error) {
+	runs := plan.Runs()
+	if plan.Direction != DirectionUp || len(runs) == 0 {
+		return "", nil, nil
+	}
+	schemas, err := b.ListSchemas(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	for _, schema := range schemas {
+		if schema.Status == Applied && schema.Version > latest {
+			latest = schema.Version
+		}
+	}
+	for _, step := range runs {
+		if step.Version < latest {
+			versions = append(versions, step.Version)
+		}
+	}
+	return latest, versions, nil
+}
+
+// checkOutOfOrder enforces the out of order policy on the out of order migrations of a plan.
+func (b *BaseMigrator) checkOutOfOrder(plan *Plan) error {
+	if len(plan.OutOfOrder) == 0 {
+		return nil
+	}
+	switch b.OutOfOrder {
+	case OutOfOrderAllow:
+		return nil
+	case OutOfOrderStrict:
+		message := fmt.Sprintf("%s sort before the latest applied version %s", strings.Join(plan.OutOfOrder, ", "), plan.LatestApplied)
+		return fmt.Errorf("%w: %s, apply them with the '%s' out of order policy", ErrOutOfOrder, message, OutOfOrderAllow)
+	case "", OutOfOrderWarn:
+		b.logger().Warn("applying out of order migrations", "versions", plan.OutOfOrder, "latest_applied", plan.LatestApplied)
+		return nil
+	default:
+		return b.OutOfOrder.Validate()
+	}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutOfOrderTestSuite struct {
+	suite.Suite
+	methods  *MockAbstractMethods
+	migrator *BaseMigrator
+}
+
+func (s *OutOfOrderTestSuite) SetupTest() {
+	s.methods = &MockAbstractMethods{}
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.methods,
+		Migrations: []Migration{
+			{Version: "20240101_initial"},
+			{Version: "20240201_feature_branch"},
+			{Version: "20240301_add_orders"},
+			{Version: "20240401_add_products"},
+		},
+	}
+	// The migration of a feature branch is merged after newer migrations were applied.
+	s.methods.On("GetSchema", mock.Anything, "20240101_initial").Return(&Schema{Status: Applied}, nil).Maybe()
+	s.methods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: Applied}, nil).Maybe()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Maybe()
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{
+		{Version: "20240101_initial", Status: Applied},
+		{Version: "20240301_add_orders", Status: Applied},
+	}, nil).Maybe()
+}
+
+func (s *OutOfOrderTestSuite) appliedVersions() []string {
+	versions := []string{}
+	for _, call := range s.methods.Calls {
+		if call.Method == "ApplyMigration" {
+			mi, _ := call.Arguments.Get(1).(Migration)
+			versions = append(versions, mi.Version)
+		}
+	}
+	return versions
+}
+
+func (s *OutOfOrderTestSuite) TestPlan_DetectsOutOfOrder() {
+	plan, err := s.migrator.Plan(context.Background(), DirectionUp, "")
+	s.Require().NoError(err)
+	s.Equal([]string{"20240201_feature_branch"}, plan.OutOfOrder)
+	s.Equal("20240301_add_orders", plan.LatestApplied)
+}
+
+func (s *OutOfOrderTestSuite) TestPlan_InOrder() {
+	s.migrator.Migrations = s.migrator.Migrations[2:]
+
+	plan, err := s.migrator.Plan(context.Background(), DirectionUp, "")
+	s.Require().NoError(err)
+	s.Empty(plan.OutOfOrder)
+}
+
+func (s *OutOfOrderTestSuite) TestPlan_ListSchemasError() {
+	errList := fmt.Errorf("list failed")
+	s.methods = &MockAbstractMethods{}
+	s.migrator.BaseMigratorAbstractMethods = s.methods
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound)
+	s.methods.On("ListSchemas", mock.Anything).Return(nil, errList).Once()
+
+	_, err := s.migrator.Plan(context.Background(), DirectionUp, "")
+	s.ErrorIs(err, errList)
+}
+
+func (s *OutOfOrderTestSuite) TestUp_Strict() {
+	s.migrator.OutOfOrder = OutOfOrderStrict
+
+	err := s.migrator.Up(context.Background(), "")
+	s.ErrorIs(err, ErrOutOfOrder)
+	s.ErrorContains(err, "20240201_feature_branch sort before the latest applied version 20240301_add_orders")
+	s.Empty(s.appliedVersions())
+}
+
+func (s *OutOfOrderTestSuite) TestUp_Warn() {
+	s.migrator.OutOfOrder = OutOfOrderWarn
+	var logs bytes.Buffer
+	s.migrator.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+	s.methods.On("ApplyMigration", mock.Anything, mock.Anything).Return(nil)
+
+	s.NoError(s.migrator.Up(context.Background(), ""))
+	s.Equal([]string{"20240201_feature_branch", "20240401_add_products"}, s.appliedVersions())
+	s.Contains(logs.String(), `level=WARN msg="applying out of order migrations" versions=[20240201_feature_branch] latest_applied=20240301_add_orders`)
+}
+
+func (s *OutOfOrderTestSuite) TestUp_DefaultPolicyWarns() {
+	s.methods.On("ApplyMigration", mock.Anything, mock.Anything).Return(nil)
+
+	s.NoError(s.migrator.Up(context.Background(), ""))
+	s.Equal([]string{"20240201_feature_branch", "20240401_add_products"}, s.appliedVersions())
+}
+
+func (s *OutOfOrderTestSuite) TestUp_Allow() {
+	s.migrator.OutOfOrder = OutOfOrderAllow
+	s.methods.On("ApplyMigration", mock.Anything, mock.Anything).Return(nil)
+
+	s.NoError(s.migrator.Up(context.Background(), ""))
+	s.Equal([]string{"20240201_feature_branch", "20240401_add_products"}, s.appliedVersions())
+}
+
+func (s *OutOfOrderTestSuite) TestUp_UnknownPolicy() {
+	s.migrator.OutOfOrder = OutOfOrderPolicy("sometimes")
+
+	err := s.migrator.Up(context.Background(), "")
+	s.ErrorContains(err, "unknown out of order policy sometimes")
+	s.Empty(s.appliedVersions())
+}
+
+func (s *OutOfOrderTestSuite) TestPolicyValidate() {
+	s.NoError(OutOfOrderPolicy("").Validate())
+	s.NoError(OutOfOrderStrict.Validate())
+	s.NoError(OutOfOrderWarn.Validate())
+	s.NoError(OutOfOrderAllow.Validate())
+	s.Error(OutOfOrderPolicy("never").Validate())
+}
+
+func TestOutOfOrderTestSuite(t *testing.T) {
+	suite.Run(t, new(OutOfOrderTestSuite))
+}
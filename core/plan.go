@@ -0,0 +1,208 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Direction is the direction of a migration run.
+type Direction string
+
+var (
+	// DirectionUp is for applying migrations
+	DirectionUp Direction = "up"
+	// DirectionDown is for reverting migrations
+	DirectionDown Direction = "down"
+)
+
+// PlanAction is what a run does with a migration.
+type PlanAction string
+
+var (
+	// ActionRun is for a migration which is executed by the run
+	ActionRun PlanAction = "run"
+	// ActionSkip is for a migration which is left untouched by the run
+	ActionSkip PlanAction = "skip"
+)
+
+// The reasons of skipped migrations.
+const (
+	ReasonApplied      = "already applied"
+	ReasonNotApplied   = "not applied"
+	ReasonDirty        = "dirty"
+	ReasonInProgress   = "in progress"
+	ReasonBeyondTarget = "beyond target"
+)
+
+// reasonBlockedBy is the reason of a pending migration whose dependency is neither applied nor run.
+func reasonBlockedBy(dependency string) string {
+	return fmt.Sprintf("blocked by dependency %s", dependency)
+}
+
+// PlanStep is a migration in a plan.
+type PlanStep struct {
+	Version string     `json:"version"`
+	Action  PlanAction `json:"action"`
+	// Reason explains why the migration is skipped.
+	Reason    string    `json:"reason,omitempty"`
+	Migration Migration `json:"-"`
+	// schema is the schema of the migration when the plan was made, nil if it has none.
+	schema *Schema
+}
+
+// Plan is the ordered list of migrations that a run goes through.
+type Plan struct {
+	Direction Direction  `json:"direction"`
+	Target    string     `json:"target,omitempty"`
+	Steps     []PlanStep `json:"steps"`
+	// Limit is the maximum number of migrations which are run, zero for no limit.
+	Limit int `json:"limit,omitempty"`
+	// OutOfOrder are the versions which are run, but sort before the latest applied version.
+	OutOfOrder    []string `json:"out_of_order,omitempty"`
+	LatestApplied string   `json:"latest_applied,omitempty"`
+}
+
+// Runs returns the steps which are executed, in order.
+func (p *Plan) Runs() []PlanStep {
+	runs := []PlanStep{}
+	for _, step := range p.Steps {
+		if step.Action == ActionRun {
+			runs = append(runs, step)
+		}
+	}
+	return runs
+}
+
+func (b *BaseMigrator) validateTarget(direction Direction, target string) error {
+	switch direction {
+	case DirectionUp:
+		if target == "" {
+			return nil
+		}
+	case DirectionDown:
+		if target == "" {
+			return fmt.Errorf("a version is required")
+		}
+	default:
+		return fmt.Errorf("unknown direction %s", direction)
+	}
+	if !b.isVersionExists(target) {
+		return fmt.Errorf("version %s does not exist", target)
+	}
+	return nil
+}
+
+// planStep decides what a run in the direction does with a migration, given its schema.
+func planStep(direction Direction, mi Migration, schema *Schema) PlanStep {
+	step := PlanStep{Version: mi.Version, Action: ActionSkip, Migration: mi, schema: schema}
+	if schema == nil {
+		if direction == DirectionUp {
+			step.Action = ActionRun
+		} else {
+			step.Reason = ReasonNotApplied
+		}
+		return step
+	}
+	switch schema.Status {
+	case Applied:
+		if direction == DirectionDown {
+			step.Action = ActionRun
+		} else {
+			step.Reason = ReasonApplied
+		}
+	case Dirty:
+		// A dirty migration is reverted on the way down, but never re-applied implicitly.
+		if direction == DirectionDown {
+			step.Action = ActionRun
+		} else {
+			step.Reason = ReasonDirty
+		}
+	case InProgress:
+		step.Reason = ReasonInProgress
+	default:
+		step.Reason = fmt.Sprintf("unknown status %s", schema.Status)
+	}
+	return step
+}
+
+// Plan computes the migrations that Up (toVersion) or Down (atVersion) would execute, without executing them.
+// Up goes through the migrations in the order of their dependencies until the target, Down goes backward until the target.
+func (b *BaseMigrator) Plan(ctx context.Context, direction Direction, target string) (*Plan, error) {
+	if err := b.validateTarget(direction, target); err != nil {
+		return nil, err
+	}
+	return b.plan(ctx, direction, target, 0)
+}
+
+// PlanSteps computes the migrations that UpSteps or DownSteps would execute, without executing them.
+// The plan stops after n migrations are run, the migrations after them are beyond the target.
+func (b *BaseMigrator) PlanSteps(ctx context.Context, direction Direction, n int) (*Plan, error) {
+	if err := validateSteps(direction, n); err != nil {
+		return nil, err
+	}
+	return b.plan(ctx, direction, "", n)
+}
+
+func validateSteps(direction Direction, n int) error {
+	if direction != DirectionUp && direction != DirectionDown {
+		return fmt.Errorf("unknown direction %s", direction)
+	}
+	if n <= 0 {
+		return fmt.Errorf("the number of steps must be positive, got %d", n)
+	}
+	return nil
+}
+
+// plan goes through the migrations in the direction until the target, or until limit migrations are run.
+// An empty target and a zero limit go through all the migrations.
+func (b *BaseMigrator) plan(ctx context.Context, direction Direction, target string, limit int) (*Plan, error) {
+	migrations, err := SortMigrations(b.Migrations)
+	if err != nil {
+		return nil, err
+	}
+	if direction == DirectionDown {
+		for i, j := 0, len(migrations)-1; i < j; i, j = i+1, j-1 {
+			migrations[i], migrations[j] = migrations[j], migrations[i]
+		}
+	}
+
+	plan := &Plan{Direction: direction, Target: target, Limit: limit, Steps: make([]PlanStep, 0, len(migrations))}
+	// satisfied are the versions which are applied, or applied by the run.
+	satisfied := map[string]bool{}
+	reached, runs := false, 0
+	for _, mi := range migrations {
+		if reached {
+			plan.Steps = append(plan.Steps, PlanStep{Version: mi.Version, Action: ActionSkip, Reason: ReasonBeyondTarget, Migration: mi})
+			continue
+		}
+		schema, err := b.GetSchema(ctx, mi.Version)
+		if err != nil && !errors.Is(err, ErrSchemaNotFound) {
+			return nil, fmt.Errorf("failed to get schema: %w", err)
+		}
+		step := planStep(direction, mi, schema)
+		if direction == DirectionDown && step.Action == ActionRun && mi.Down == nil && !b.AllowIrreversible {
+			return nil, fmt.Errorf("%w: %s has no down, allow irreversible migrations to forget it without reverting it", ErrIrreversible, mi.Version)
+		}
+		if direction == DirectionUp && step.Action == ActionRun {
+			for _, dependency := range mi.DependsOn {
+				if !satisfied[dependency] {
+					step.Action, step.Reason = ActionSkip, reasonBlockedBy(dependency)
+					break
+				}
+			}
+		}
+		if (schema != nil && schema.Status == Applied) || (direction == DirectionUp && step.Action == ActionRun) {
+			satisfied[mi.Version] = true
+		}
+		plan.Steps = append(plan.Steps, step)
+		if step.Action == ActionRun {
+			runs++
+		}
+		reached = mi.Version == target || (limit > 0 && runs == limit)
+	}
+	if plan.LatestApplied, plan.OutOfOrder, err = b.outOfOrder(ctx, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
@@ -0,0 +1,267 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PlanTestSuite struct {
+	suite.Suite
+	methods  *MockAbstractMethods
+	migrator *BaseMigrator
+}
+
+func (s *PlanTestSuite) SetupTest() {
+	s.methods = &MockAbstractMethods{}
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.methods,
+		Migrations: []Migration{
+			{Version: "20240101_initial", Down: noopMutation},
+			{Version: "20240201_add_users", Down: noopMutation},
+			{Version: "20240301_add_orders", Down: noopMutation},
+			{Version: "20240401_add_products", Down: noopMutation},
+		},
+	}
+}
+
+func (s *PlanTestSuite) stepsOf(plan *Plan) []PlanStep {
+	steps := make([]PlanStep, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		step.Migration, step.schema = Migration{}, nil
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func (s *PlanTestSuite) TestPlan_InvalidTarget() {
+	_, err := s.migrator.Plan(context.Background(), DirectionUp, "20240501_nonexistent")
+	s.ErrorContains(err, "version 20240501_nonexistent does not exist")
+	_, err = s.migrator.Plan(context.Background(), DirectionDown, "")
+	s.ErrorContains(err, "a version is required")
+	_, err = s.migrator.Plan(context.Background(), Direction("sideways"), "")
+	s.ErrorContains(err, "unknown direction sideways")
+}
+
+func (s *PlanTestSuite) TestPlan_GetSchemaError() {
+	errGetSchema := fmt.Errorf("get schema failed")
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, errGetSchema).Once()
+
+	_, err := s.migrator.Plan(context.Background(), DirectionUp, "")
+	s.ErrorIs(err, errGetSchema)
+}
+
+func (s *PlanTestSuite) TestPlan_Up() {
+	s.methods.On("GetSchema", mock.Anything, "20240101_initial").Return(&Schema{Status: Applied}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Dirty}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: InProgress}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240401_add_products").Return(nil, ErrSchemaNotFound).Once()
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{{Version: "20240101_initial", Status: Applied}}, nil).Once()
+
+	plan, err := s.migrator.Plan(context.Background(), DirectionUp, "")
+	s.Require().NoError(err)
+	s.Equal(DirectionUp, plan.Direction)
+	s.Equal([]PlanStep{
+		{Version: "20240101_initial", Action: ActionSkip, Reason: ReasonApplied},
+		{Version: "20240201_add_users", Action: ActionSkip, Reason: ReasonDirty},
+		{Version: "20240301_add_orders", Action: ActionSkip, Reason: ReasonInProgress},
+		{Version: "20240401_add_products", Action: ActionRun},
+	}, s.stepsOf(plan))
+	s.Require().Len(plan.Runs(), 1)
+	s.Equal("20240401_add_products", plan.Runs()[0].Migration.Version)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestPlan_UpToVersion() {
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Times(2)
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{}, nil).Once()
+
+	plan, err := s.migrator.Plan(context.Background(), DirectionUp, "20240201_add_users")
+	s.Require().NoError(err)
+	s.Equal([]PlanStep{
+		{Version: "20240101_initial", Action: ActionRun},
+		{Version: "20240201_add_users", Action: ActionRun},
+		{Version: "20240301_add_orders", Action: ActionSkip, Reason: ReasonBeyondTarget},
+		{Version: "20240401_add_products", Action: ActionSkip, Reason: ReasonBeyondTarget},
+	}, s.stepsOf(plan))
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestPlan_Down() {
+	s.methods.On("GetSchema", mock.Anything, "20240401_add_products").Return(nil, ErrSchemaNotFound).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: Dirty}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Applied}, nil).Once()
+
+	plan, err := s.migrator.Plan(context.Background(), DirectionDown, "20240201_add_users")
+	s.Require().NoError(err)
+	s.Equal(DirectionDown, plan.Direction)
+	s.Equal("20240201_add_users", plan.Target)
+	s.Equal([]PlanStep{
+		{Version: "20240401_add_products", Action: ActionSkip, Reason: ReasonNotApplied},
+		{Version: "20240301_add_orders", Action: ActionRun},
+		{Version: "20240201_add_users", Action: ActionRun},
+		{Version: "20240101_initial", Action: ActionSkip, Reason: ReasonBeyondTarget},
+	}, s.stepsOf(plan))
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestPlanSteps_InvalidSteps() {
+	_, err := s.migrator.PlanSteps(context.Background(), DirectionUp, 0)
+	s.ErrorContains(err, "the number of steps must be positive, got 0")
+	_, err = s.migrator.PlanSteps(context.Background(), Direction("sideways"), 1)
+	s.ErrorContains(err, "unknown direction sideways")
+}
+
+func (s *PlanTestSuite) TestPlanSteps_Up() {
+	s.methods.On("GetSchema", mock.Anything, "20240101_initial").Return(&Schema{Status: Applied}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Dirty}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(nil, ErrSchemaNotFound).Once()
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{{Version: "20240101_initial", Status: Applied}}, nil).Once()
+
+	plan, err := s.migrator.PlanSteps(context.Background(), DirectionUp, 1)
+	s.Require().NoError(err)
+	s.Equal(1, plan.Limit)
+	s.Empty(plan.Target)
+	s.Equal([]PlanStep{
+		{Version: "20240101_initial", Action: ActionSkip, Reason: ReasonApplied},
+		{Version: "20240201_add_users", Action: ActionSkip, Reason: ReasonDirty},
+		{Version: "20240301_add_orders", Action: ActionRun},
+		{Version: "20240401_add_products", Action: ActionSkip, Reason: ReasonBeyondTarget},
+	}, s.stepsOf(plan))
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestPlanSteps_Down() {
+	s.methods.On("GetSchema", mock.Anything, "20240401_add_products").Return(nil, ErrSchemaNotFound).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: Dirty}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Applied}, nil).Once()
+
+	plan, err := s.migrator.PlanSteps(context.Background(), DirectionDown, 2)
+	s.Require().NoError(err)
+	s.Equal([]PlanStep{
+		{Version: "20240401_add_products", Action: ActionSkip, Reason: ReasonNotApplied},
+		{Version: "20240301_add_orders", Action: ActionRun},
+		{Version: "20240201_add_users", Action: ActionRun},
+		{Version: "20240101_initial", Action: ActionSkip, Reason: ReasonBeyondTarget},
+	}, s.stepsOf(plan))
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestPlanSteps_MoreStepsThanPending() {
+	s.methods.On("GetSchema", mock.Anything, "20240101_initial").Return(&Schema{Status: Applied}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Times(3)
+
+	plan, err := s.migrator.PlanSteps(context.Background(), DirectionDown, 3)
+	s.Require().NoError(err)
+	s.Require().Len(plan.Runs(), 1)
+	s.Equal("20240101_initial", plan.Runs()[0].Version)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestPlan_DownIrreversible() {
+	s.migrator.Migrations[2].Down = nil
+	s.methods.On("GetSchema", mock.Anything, "20240401_add_products").Return(nil, ErrSchemaNotFound).Twice()
+	s.methods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: Applied}, nil).Twice()
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Applied}, nil).Once()
+
+	_, err := s.migrator.Plan(context.Background(), DirectionDown, "20240201_add_users")
+	s.ErrorIs(err, ErrIrreversible)
+
+	// Allowed, the irreversible migration is planned as any other.
+	s.migrator.AllowIrreversible = true
+	plan, err := s.migrator.Plan(context.Background(), DirectionDown, "20240201_add_users")
+	s.Require().NoError(err)
+	s.Len(plan.Runs(), 2)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestPlan_DownSkipsIrreversibleNotApplied() {
+	s.migrator.Migrations[3].Down = nil
+	s.methods.On("GetSchema", mock.Anything, "20240401_add_products").Return(nil, ErrSchemaNotFound).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240301_add_orders").Return(&Schema{Status: Applied}, nil).Once()
+
+	plan, err := s.migrator.Plan(context.Background(), DirectionDown, "20240301_add_orders")
+	s.Require().NoError(err)
+	s.Len(plan.Runs(), 1)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestUp_ExecutesPlanInOrder() {
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Applied}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Times(2)
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{{Version: "20240201_add_users", Status: Applied}}, nil).Once()
+	applied := []string{}
+	s.methods.On("ApplyMigration", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		mi, _ := args.Get(1).(Migration)
+		applied = append(applied, mi.Version)
+	}).Return(nil).Times(2)
+
+	err := s.migrator.Up(context.Background(), "20240301_add_orders")
+	s.Require().NoError(err)
+	s.Equal([]string{"20240101_initial", "20240301_add_orders"}, applied)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestPlan_UpInDependencyOrder() {
+	s.migrator.Migrations[1].DependsOn = []string{"20240401_add_products"}
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Times(4)
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{}, nil).Once()
+
+	plan, err := s.migrator.Plan(context.Background(), DirectionUp, "")
+	s.Require().NoError(err)
+	s.Equal([]PlanStep{
+		{Version: "20240101_initial", Action: ActionRun},
+		{Version: "20240301_add_orders", Action: ActionRun},
+		{Version: "20240401_add_products", Action: ActionRun},
+		{Version: "20240201_add_users", Action: ActionRun},
+	}, s.stepsOf(plan))
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *PlanTestSuite) TestPlan_UpBlockedByDependency() {
+	s.migrator.Migrations[2].DependsOn = []string{"20240201_add_users"}
+	s.migrator.Migrations[3].DependsOn = []string{"20240301_add_orders"}
+	s.methods.On("GetSchema", mock.Anything, "20240101_initial").Return(&Schema{Status: Applied}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Dirty}, nil).Once()
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(nil, ErrSchemaNotFound).Times(2)
+
+	plan, err := s.migrator.Plan(context.Background(), DirectionUp, "")
+	s.Require().NoError(err)
+	s.Equal([]PlanStep{
+		{Version: "20240101_initial", Action: ActionSkip, Reason: ReasonApplied},
+		{Version: "20240201_add_users", Action: ActionSkip, Reason: ReasonDirty},
+		{Version: "20240301_add_orders", Action: ActionSkip, Reason: "blocked by dependency 20240201_add_users"},
+		{Version: "20240401_add_products", Action: ActionSkip, Reason: "blocked by dependency 20240301_add_orders"},
+	}, s.stepsOf(plan))
+	s.Empty(plan.Runs())
+}
+
+func (s *PlanTestSuite) TestPlan_DownInReverseDependencyOrder() {
+	s.migrator.Migrations[1].DependsOn = []string{"20240401_add_products"}
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: Applied}, nil).Times(3)
+
+	plan, err := s.migrator.Plan(context.Background(), DirectionDown, "20240301_add_orders")
+	s.Require().NoError(err)
+	s.Equal([]PlanStep{
+		{Version: "20240201_add_users", Action: ActionRun},
+		{Version: "20240401_add_products", Action: ActionRun},
+		{Version: "20240301_add_orders", Action: ActionRun},
+		{Version: "20240101_initial", Action: ActionSkip, Reason: ReasonBeyondTarget},
+	}, s.stepsOf(plan))
+}
+
+func (s *PlanTestSuite) TestPlan_InvalidDependencies() {
+	s.migrator.Migrations[0].DependsOn = []string{"20240401_add_products"}
+	s.migrator.Migrations[3].DependsOn = []string{"20240101_initial"}
+
+	_, err := s.migrator.Plan(context.Background(), DirectionUp, "")
+	s.ErrorContains(err, "dependency cycle between migrations")
+	s.methods.AssertNotCalled(s.T(), "GetSchema", mock.Anything, mock.Anything)
+}
+
+func TestPlanTestSuite(t *testing.T) {
+	suite.Run(t, new(PlanTestSuite))
+}
@@ -0,0 +1,92 @@
+package plugintest
+
+import (
+	"context"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+const (
+	owner      = "plugintest-owner-1"
+	otherOwner = "plugintest-owner-2"
+)
+
+// locker returns the plugin as a core.Locker, the test is skipped if it is not one.
+// The locks of the owners are released at the end of the test.
+func (s *conformanceSuite) locker(plugin core.BaseMigratorAbstractMethods) core.Locker {
+	locker, ok := plugin.(core.Locker)
+	if !ok {
+		s.T().Skip("the plugin does not implement core.Locker")
+	}
+	s.T().Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		_ = locker.ReleaseLock(ctx, owner)
+		_ = locker.ReleaseLock(ctx, otherOwner)
+	})
+	return locker
+}
+
+func (s *conformanceSuite) TestLock_Acquire() {
+	locker := s.locker(s.plugin)
+	lock, err := locker.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(lock, "GetLock of a free lock")
+
+	s.Require().NoError(locker.AcquireLock(s.ctx, owner, time.Minute))
+	lock, err = locker.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(lock, "GetLock of a held lock")
+	s.Require().Equal(owner, lock.Owner)
+	s.Require().False(lock.IsExpired())
+}
+
+func (s *conformanceSuite) TestLock_HeldByAnotherOwner() {
+	locker := s.locker(s.plugin)
+	other := s.locker(s.factory(s.T()))
+	s.Require().NoError(locker.AcquireLock(s.ctx, owner, time.Minute))
+	// Another migrator cannot take the held lock.
+	err := other.AcquireLock(s.ctx, otherOwner, time.Minute)
+	s.Require().ErrorIs(err, core.ErrLockHeld)
+	// It takes it once released.
+	s.Require().NoError(locker.ReleaseLock(s.ctx, owner))
+	s.Require().NoError(other.AcquireLock(s.ctx, otherOwner, time.Minute))
+}
+
+func (s *conformanceSuite) TestLock_Refresh() {
+	locker := s.locker(s.plugin)
+	s.Require().NoError(locker.AcquireLock(s.ctx, owner, time.Minute))
+	s.Require().NoError(locker.RefreshLock(s.ctx, owner, time.Minute))
+	// A non-owner cannot refresh the lock.
+	err := locker.RefreshLock(s.ctx, otherOwner, time.Minute)
+	s.Require().ErrorIs(err, core.ErrLockLost)
+}
+
+func (s *conformanceSuite) TestLock_Release() {
+	locker := s.locker(s.plugin)
+	s.Require().NoError(locker.AcquireLock(s.ctx, owner, time.Minute))
+	// Releasing the lock of another owner is a no-op.
+	s.Require().NoError(locker.ReleaseLock(s.ctx, otherOwner))
+	lock, err := locker.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(lock, "GetLock after the release of a non-owner")
+	// The owner releases the lock.
+	s.Require().NoError(locker.ReleaseLock(s.ctx, owner))
+	lock, err = locker.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(lock, "GetLock after the release of the owner")
+}
+
+func (s *conformanceSuite) TestLock_ForceRelease() {
+	locker := s.locker(s.plugin)
+	other := s.locker(s.factory(s.T()))
+	s.Require().NoError(locker.AcquireLock(s.ctx, owner, time.Hour))
+	// Another migrator recovers the lock of a crashed holder.
+	s.Require().NoError(other.ForceReleaseLock(s.ctx))
+	s.Require().Eventually(func() bool {
+		lock, err := other.GetLock(s.ctx)
+		return err == nil && lock == nil
+	}, 5*time.Second, 100*time.Millisecond, "the lock is not released")
+	s.Require().NoError(other.AcquireLock(s.ctx, otherOwner, time.Minute))
+}
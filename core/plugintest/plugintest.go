@@ -0,0 +1,257 @@
+// Package plugintest provides conformance tests for gomiger database plugins.
+// A plugin runs the whole kit with Run, or a single contract from its own test suite against a connected instance.
+package plugintest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// timeout bounds each conformance test.
+const timeout = 10 * time.Second
+
+func noop(context.Context) error { return nil }
+
+// AssertSchemaNotFound pins the contract of GetSchema for versions without schema:
+// the plugin must return an error matching core.ErrSchemaNotFound, so the base migrator treats them as pending.
+// The plugin must be connected to an empty schema store.
+func AssertSchemaNotFound(t *testing.T, plugin core.BaseMigratorAbstractMethods) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// A version which has never been applied.
+	_, err := plugin.GetSchema(ctx, "plugintest_never_applied")
+	require.ErrorIs(t, err, core.ErrSchemaNotFound, "GetSchema of a never applied version")
+
+	// Applying a version does not create the schema of another version.
+	mi := core.Migration{Version: "plugintest_applied", Up: noop, Down: noop}
+	require.NoError(t, plugin.ApplyMigration(ctx, mi))
+	_, err = plugin.GetSchema(ctx, "plugintest_never_applied")
+	require.ErrorIs(t, err, core.ErrSchemaNotFound, "GetSchema of a never applied version next to an applied one")
+
+	// A reverted version is pending again.
+	require.NoError(t, plugin.RevertMigration(ctx, mi))
+	_, err = plugin.GetSchema(ctx, mi.Version)
+	require.ErrorIs(t, err, core.ErrSchemaNotFound, "GetSchema of a reverted version")
+}
+
+// Factory creates a plugin connected to an empty schema store, and registers its cleanup on t.
+// The lock tests call it twice in a test: both plugins must share the schema store & the lock.
+type Factory func(t *testing.T) core.BaseMigratorAbstractMethods
+
+// Run runs the conformance suite against the plugins of the factory, one plugin per test.
+// The lock tests are skipped if the plugin does not implement core.Locker.
+func Run(t *testing.T, factory Factory) {
+	t.Helper()
+	suite.Run(t, &conformanceSuite{factory: factory})
+}
+
+type conformanceSuite struct {
+	suite.Suite
+	factory Factory
+	plugin  core.BaseMigratorAbstractMethods
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func (s *conformanceSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), timeout)
+	s.plugin = s.factory(s.T())
+}
+
+func (s *conformanceSuite) TearDownTest() {
+	s.cancel()
+}
+
+// migration returns a migration which counts the runs of its Up & Down functions, and fails them with err.
+// The failing migrations disable the transaction, so the transactional plugins mark them as dirty too.
+func (s *conformanceSuite) migration(version string, ups, downs *int, err error) core.Migration {
+	return core.Migration{
+		Version: version,
+		Up: func(context.Context) error {
+			*ups++
+			time.Sleep(10 * time.Millisecond)
+			return err
+		},
+		Down: func(context.Context) error {
+			*downs++
+			return err
+		},
+		Checksum:           "checksum-" + version,
+		DisableTransaction: err != nil,
+	}
+}
+
+func (s *conformanceSuite) requireStatus(version string, status core.SchemaStatus) *core.Schema {
+	schema, err := s.plugin.GetSchema(s.ctx, version)
+	s.Require().NoError(err, "GetSchema of %s", version)
+	s.Require().Equal(status, schema.Status, "status of %s", version)
+	return schema
+}
+
+func (s *conformanceSuite) TestSchemaNotFound() {
+	AssertSchemaNotFound(s.T(), s.plugin)
+}
+
+func (s *conformanceSuite) TestConnect_Idempotent() {
+	err := s.plugin.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+	// Connecting again keeps the schema store.
+	s.Require().NoError(s.plugin.Connect(s.ctx), "second Connect")
+	s.requireStatus("1.0.0", core.Applied)
+}
+
+func (s *conformanceSuite) TestSaveSchema_RoundTrip() {
+	expected := core.Schema{
+		Version:   "1.0.0",
+		Timestamp: time.Now(),
+		Status:    core.Dirty,
+		Duration:  time.Second,
+		Checksum:  "checksum-v1",
+		// The execution metadata.
+		AppliedBy:      "deploy@ci-runner-1",
+		GomigerVersion: "v1.0.0",
+		AppVersion:     "v2.3.4",
+		Direction:      core.DirectionDown,
+		Error:          "plugintest: down failed",
+	}
+	s.Require().NoError(s.plugin.SaveSchema(s.ctx, expected))
+	schema := s.requireStatus("1.0.0", core.Dirty)
+	s.Require().WithinDuration(expected.Timestamp, schema.Timestamp, time.Millisecond)
+	s.Require().Equal(expected.Duration, schema.Duration)
+	s.Require().Equal(expected.Checksum, schema.Checksum)
+	s.Require().False(schema.Baselined)
+	s.Require().Equal(expected.AppliedBy, schema.AppliedBy)
+	s.Require().Equal(expected.GomigerVersion, schema.GomigerVersion)
+	s.Require().Equal(expected.AppVersion, schema.AppVersion)
+	s.Require().Equal(expected.Direction, schema.Direction)
+	s.Require().Equal(expected.Error, schema.Error)
+	// Saving a version again replaces its schema.
+	expected.Status, expected.Baselined, expected.Direction, expected.Error = core.Applied, true, core.DirectionUp, ""
+	s.Require().NoError(s.plugin.SaveSchema(s.ctx, expected))
+	schema = s.requireStatus("1.0.0", core.Applied)
+	s.Require().True(schema.Baselined)
+	s.Require().Equal(core.DirectionUp, schema.Direction)
+	s.Require().Empty(schema.Error)
+	schemas, err := s.plugin.ListSchemas(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(schemas, 1)
+}
+
+func (s *conformanceSuite) TestDeleteSchema() {
+	s.Require().NoError(s.plugin.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Dirty, Timestamp: time.Now()}))
+	s.Require().NoError(s.plugin.DeleteSchema(s.ctx, "1.0.0"))
+	_, err := s.plugin.GetSchema(s.ctx, "1.0.0")
+	s.Require().ErrorIs(err, core.ErrSchemaNotFound)
+	// Deleting a missing schema is not an error.
+	s.Require().NoError(s.plugin.DeleteSchema(s.ctx, "1.0.0"), "DeleteSchema of a missing version")
+}
+
+func (s *conformanceSuite) TestListSchemas_Empty() {
+	schemas, err := s.plugin.ListSchemas(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(schemas)
+}
+
+func (s *conformanceSuite) TestListSchemas_SortedByVersion() {
+	for _, version := range []string{"3.0.0", "1.0.0", "2.0.0"} {
+		s.Require().NoError(s.plugin.SaveSchema(s.ctx, core.Schema{Version: version, Status: core.Applied, Timestamp: time.Now()}))
+	}
+	schemas, err := s.plugin.ListSchemas(s.ctx)
+	s.Require().NoError(err)
+	versions := []string{}
+	for _, schema := range schemas {
+		versions = append(versions, schema.Version)
+	}
+	s.Require().Equal([]string{"1.0.0", "2.0.0", "3.0.0"}, versions)
+}
+
+func (s *conformanceSuite) TestApplyMigration_Success() {
+	var ups, downs int
+	startedAt := time.Now()
+	s.Require().NoError(s.plugin.ApplyMigration(s.ctx, s.migration("1.0.0", &ups, &downs, nil)))
+	s.Require().Equal(1, ups)
+	schema := s.requireStatus("1.0.0", core.Applied)
+	s.Require().Equal("checksum-1.0.0", schema.Checksum)
+	s.Require().GreaterOrEqual(schema.Duration, 10*time.Millisecond)
+	s.Require().WithinDuration(startedAt, schema.Timestamp, time.Second)
+}
+
+func (s *conformanceSuite) TestApplyMigration_DuplicateVersion() {
+	var ups, downs int
+	s.Require().NoError(s.plugin.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()}))
+	err := s.plugin.ApplyMigration(s.ctx, s.migration("1.0.0", &ups, &downs, nil))
+	s.Require().Error(err, "ApplyMigration of an applied version")
+	// A transactional plugin may run Up before the conflict, as it is rolled back.
+	s.requireStatus("1.0.0", core.Applied)
+}
+
+func (s *conformanceSuite) TestApplyMigration_FailureMarksDirty() {
+	var ups, downs int
+	errUp := errors.New("plugintest: up failed")
+	err := s.plugin.ApplyMigration(s.ctx, s.migration("1.0.0", &ups, &downs, errUp))
+	s.Require().ErrorIs(err, errUp)
+	s.requireStatus("1.0.0", core.Dirty)
+}
+
+func (s *conformanceSuite) TestUp_RecordsTheExecution() {
+	migrator := &core.BaseMigrator{
+		BaseMigratorAbstractMethods: s.plugin,
+		Migrations:                  []core.Migration{{Version: "1.0.0", Up: noop, Down: noop}},
+		AppVersion:                  "v1.2.3",
+	}
+	s.Require().NoError(migrator.Up(s.ctx, ""))
+	schema := s.requireStatus("1.0.0", core.Applied)
+	s.Require().NotEmpty(schema.AppliedBy)
+	s.Require().Equal("v1.2.3", schema.AppVersion)
+	s.Require().Equal(core.DirectionUp, schema.Direction)
+	s.Require().Empty(schema.Error)
+}
+
+func (s *conformanceSuite) TestUp_CancelledMarksDirty() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	migrator := &core.BaseMigrator{
+		BaseMigratorAbstractMethods: s.plugin,
+		Migrations: []core.Migration{{
+			Version: "1.0.0",
+			Up: func(ctx context.Context) error {
+				cancel()
+				return ctx.Err()
+			},
+			Down:               noop,
+			DisableTransaction: true,
+		}},
+	}
+	s.Require().ErrorIs(migrator.Up(ctx, ""), context.Canceled)
+	// The failure is recorded, although the context of the run is cancelled.
+	schema := s.requireStatus("1.0.0", core.Dirty)
+	s.Require().Equal(core.DirectionUp, schema.Direction)
+	s.Require().Equal(context.Canceled.Error(), schema.Error)
+}
+
+func (s *conformanceSuite) TestRevertMigration_Success() {
+	var ups, downs int
+	mi := s.migration("1.0.0", &ups, &downs, nil)
+	s.Require().NoError(s.plugin.ApplyMigration(s.ctx, mi))
+	s.Require().NoError(s.plugin.RevertMigration(s.ctx, mi))
+	s.Require().Equal(1, downs)
+	_, err := s.plugin.GetSchema(s.ctx, "1.0.0")
+	s.Require().ErrorIs(err, core.ErrSchemaNotFound)
+}
+
+func (s *conformanceSuite) TestRevertMigration_FailureMarksDirty() {
+	var ups, downs int
+	errDown := errors.New("plugintest: down failed")
+	s.Require().NoError(s.plugin.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()}))
+	err := s.plugin.RevertMigration(s.ctx, s.migration("1.0.0", &ups, &downs, errDown))
+	s.Require().ErrorIs(err, errDown)
+	s.requireStatus("1.0.0", core.Dirty)
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Force sets the state of a version without executing its migration.
+// StateApplied records the version as applied, StatePending deletes its schema so it runs again on the next Up.
+func (b *BaseMigrator) Force(ctx context.Context, version string, state MigrationState) (err error) {
+	if state != StateApplied && state != StatePending {
+		return fmt.Errorf("cannot force version %s to %s, only %s and %s are allowed", version, state, StateApplied, StatePending)
+	}
+	if state == StateApplied && !b.isVersionExists(version) {
+		return fmt.Errorf("version %s does not exist", version)
+	}
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+	if state == StatePending {
+		if err := b.DeleteSchema(ctx, version); err != nil {
+			return fmt.Errorf("failed to force version %s to %s: %w", version, state, err)
+		}
+		return nil
+	}
+	schema := Schema{Version: version, Timestamp: time.Now(), Status: Applied, Checksum: b.checksumOf(version)}
+	b.execution("").Stamp(&schema, nil)
+	if err := b.SaveSchema(ctx, schema); err != nil {
+		return fmt.Errorf("failed to force version %s to %s: %w", version, state, err)
+	}
+	return nil
+}
+
+// Retry applies a dirty or in progress migration again, with its hooks & events as in an up run.
+// Make sure the changes of the failed attempt are cleaned up, or that the migration is idempotent.
+// The schema stays dirty if the retry fails.
+func (b *BaseMigrator) Retry(ctx context.Context, version string) (err error) {
+	if !b.isVersionExists(version) {
+		return fmt.Errorf("version %s does not exist", version)
+	}
+	ctx, unlock, err := b.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, unlock()) }()
+	schema, err := b.GetSchema(ctx, version)
+	if err != nil {
+		return fmt.Errorf("failed to get schema: %w", err)
+	}
+	if schema.Status != Dirty && schema.Status != InProgress {
+		return fmt.Errorf("version %s is %s, only dirty or in progress versions can be retried", version, schema.Status)
+	}
+	if err := b.DeleteSchema(ctx, version); err != nil {
+		return fmt.Errorf("failed to reset schema at version %s: %w", version, err)
+	}
+	for _, mi := range b.Migrations {
+		if mi.Version != version {
+			continue
+		}
+		if err := b.applyStep(ctx, mi); err != nil {
+			// A failed hook or a rolled back transaction leaves no schema, restore the failed one.
+			restoreCtx := context.WithoutCancel(ctx)
+			if _, getErr := b.GetSchema(restoreCtx, version); errors.Is(getErr, ErrSchemaNotFound) {
+				if saveErr := b.SaveSchema(restoreCtx, *schema); saveErr != nil {
+					err = errors.Join(err, fmt.Errorf("failed to restore schema at version %s: %w", version, saveErr))
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Repair lists the dirty and in progress schemas which are older than the threshold.
+// Recover them with Retry or Force.
+func (b *BaseMigrator) Repair(ctx context.Context, olderThan time.Duration) ([]Schema, error) {
+	schemas, err := b.ListSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	threshold := time.Now().Add(-olderThan)
+	broken := []Schema{}
+	for _, schema := range schemas {
+		if schema.Status != Dirty && schema.Status != InProgress {
+			continue
+		}
+		if schema.Timestamp.After(threshold) {
+			continue
+		}
+		broken = append(broken, schema)
+	}
+	return broken, nil
+}
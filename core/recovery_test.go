@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecoveryTestSuite struct {
+	suite.Suite
+	methods  *MockAbstractMethods
+	migrator *BaseMigrator
+}
+
+func (s *RecoveryTestSuite) SetupTest() {
+	s.methods = &MockAbstractMethods{}
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.methods,
+		Migrations: []Migration{
+			{Version: "20240101_initial"},
+			{Version: "20240201_add_users"},
+		},
+	}
+}
+
+func (s *RecoveryTestSuite) TestForce_InvalidState() {
+	err := s.migrator.Force(context.Background(), "20240101_initial", StateDirty)
+	s.ErrorContains(err, "only applied and pending are allowed")
+}
+
+func (s *RecoveryTestSuite) TestForce_Applied() {
+	s.methods.On("SaveSchema", mock.Anything, mock.MatchedBy(func(schema Schema) bool {
+		return schema.Version == "20240101_initial" && schema.Status == Applied && !schema.Timestamp.IsZero()
+	})).Return(nil).Once()
+
+	err := s.migrator.Force(context.Background(), "20240101_initial", StateApplied)
+	s.NoError(err)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *RecoveryTestSuite) TestForce_AppliedNonexistentVersion() {
+	err := s.migrator.Force(context.Background(), "20240401_nonexistent", StateApplied)
+	s.ErrorContains(err, "version 20240401_nonexistent does not exist")
+}
+
+func (s *RecoveryTestSuite) TestForce_Pending() {
+	// Orphaned versions can be forced to pending.
+	s.methods.On("DeleteSchema", mock.Anything, "20231201_removed").Return(nil).Once()
+
+	err := s.migrator.Force(context.Background(), "20231201_removed", StatePending)
+	s.NoError(err)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *RecoveryTestSuite) TestForce_SaveSchemaError() {
+	errSave := fmt.Errorf("save failed")
+	s.methods.On("SaveSchema", mock.Anything, mock.Anything).Return(errSave).Once()
+
+	err := s.migrator.Force(context.Background(), "20240101_initial", StateApplied)
+	s.ErrorIs(err, errSave)
+}
+
+func (s *RecoveryTestSuite) TestRetry_Dirty() {
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Dirty}, nil).Once()
+	s.methods.On("DeleteSchema", mock.Anything, "20240201_add_users").Return(nil).Once()
+	s.methods.On("ApplyMigration", mock.Anything, mock.MatchedBy(func(mi Migration) bool {
+		return mi.Version == "20240201_add_users"
+	})).Return(nil).Once()
+
+	err := s.migrator.Retry(context.Background(), "20240201_add_users")
+	s.NoError(err)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *RecoveryTestSuite) TestRetry_NotDirty() {
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(&Schema{Status: Applied}, nil).Once()
+
+	err := s.migrator.Retry(context.Background(), "20240201_add_users")
+	s.ErrorContains(err, "only dirty or in progress versions can be retried")
+	s.methods.AssertNotCalled(s.T(), "DeleteSchema", mock.Anything, mock.Anything)
+}
+
+func (s *RecoveryTestSuite) TestRetry_NeverApplied() {
+	s.methods.On("GetSchema", mock.Anything, "20240201_add_users").Return(nil, ErrSchemaNotFound).Once()
+
+	err := s.migrator.Retry(context.Background(), "20240201_add_users")
+	s.ErrorIs(err, ErrSchemaNotFound)
+}
+
+func (s *RecoveryTestSuite) TestRetry_ApplyMigrationError() {
+	errApply := fmt.Errorf("apply failed")
+	s.methods.On("GetSchema", mock.Anything, mock.Anything).Return(&Schema{Status: InProgress}, nil).Once()
+	s.methods.On("DeleteSchema", mock.Anything, mock.Anything).Return(nil).Once()
+	s.methods.On("ApplyMigration", mock.Anything, mock.Anything).Return(errApply).Once()
+	// The plugin marked the migration as dirty again, it is not restored.
+	s.methods.On("GetSchema", mock.Anything, "20240101_initial").Return(&Schema{Status: Dirty}, nil).Once()
+
+	err := s.migrator.Retry(context.Background(), "20240101_initial")
+	s.ErrorIs(err, errApply)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *RecoveryTestSuite) TestRetry_RolledBackKeepsTheMigrationDirty() {
+	errApply := fmt.Errorf("apply failed")
+	dirty := Schema{Version: "20240101_initial", Status: Dirty, Checksum: "sha256:initial"}
+	s.methods.On("GetSchema", mock.Anything, "20240101_initial").Return(&dirty, nil).Once()
+	s.methods.On("DeleteSchema", mock.Anything, "20240101_initial").Return(nil).Once()
+	s.methods.On("ApplyMigration", mock.Anything, mock.Anything).Return(errApply).Once()
+	// The transaction is rolled back, the migration has no schema anymore.
+	s.methods.On("GetSchema", mock.Anything, "20240101_initial").Return(nil, ErrSchemaNotFound).Once()
+	s.methods.On("SaveSchema", mock.Anything, dirty).Return(nil).Once()
+
+	err := s.migrator.Retry(context.Background(), "20240101_initial")
+	s.ErrorIs(err, errApply)
+	s.methods.AssertExpectations(s.T())
+}
+
+func (s *RecoveryTestSuite) TestRepair() {
+	old := time.Now().Add(-2 * time.Hour)
+	recent := time.Now()
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{
+		{Version: "20240101_initial", Status: Applied, Timestamp: old},
+		{Version: "20240201_add_users", Status: Dirty, Timestamp: old},
+		{Version: "20240301_add_orders", Status: InProgress, Timestamp: old},
+		{Version: "20240401_add_products", Status: InProgress, Timestamp: recent},
+	}, nil).Once()
+
+	schemas, err := s.migrator.Repair(context.Background(), time.Hour)
+	s.Require().NoError(err)
+	s.Require().Len(schemas, 2)
+	s.Equal("20240201_add_users", schemas[0].Version)
+	s.Equal("20240301_add_orders", schemas[1].Version)
+}
+
+func TestRecoveryTestSuite(t *testing.T) {
+	suite.Run(t, new(RecoveryTestSuite))
+}
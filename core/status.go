@@ -0,0 +1,93 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MigrationState is the state of a migration, merged from the code and the schema store.
+type MigrationState string
+
+var (
+	// StatePending is for a migration which has never been applied
+	StatePending MigrationState = "pending"
+	// StateInProgress is for a running migration
+	StateInProgress MigrationState = "in_progress"
+	// StateApplied is for a completed migration
+	StateApplied MigrationState = "applied"
+	// StateDirty is for a failed migration
+	StateDirty MigrationState = "dirty"
+	// StateOrphaned is for a schema in the store without migration in the code
+	StateOrphaned MigrationState = "orphaned"
+)
+
+// MigrationStatus is the state of a single migration version.
+type MigrationStatus struct {
+	Version string         `json:"version"`
+	State   MigrationState `json:"state"`
+	// AppliedAt is the timestamp of the schema, nil unless the migration is applied.
+	AppliedAt *time.Time    `json:"applied_at,omitempty"`
+	Duration  time.Duration `json:"duration,omitempty"`
+	// Baselined is for a migration recorded as applied by Baseline, without being executed.
+	Baselined bool `json:"baselined,omitempty"`
+}
+
+func stateOf(schema *Schema) MigrationState {
+	switch schema.Status {
+	case Applied:
+		return StateApplied
+	case Dirty:
+		return StateDirty
+	default:
+		return StateInProgress
+	}
+}
+
+func newMigrationStatus(version string, state MigrationState, schema *Schema) MigrationStatus {
+	status := MigrationStatus{Version: version, State: state}
+	if schema != nil {
+		if schema.Status == Applied {
+			timestamp := schema.Timestamp
+			status.AppliedAt = &timestamp
+		}
+		status.Duration = schema.Duration
+		status.Baselined = schema.Baselined
+	}
+	return status
+}
+
+// Status lists every migration in the code in the order of the runs, followed by the orphaned schemas of the store.
+func (b *BaseMigrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := SortMigrations(b.Migrations)
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := b.ListSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	schemaByVersion := make(map[string]*Schema, len(schemas))
+	for i := range schemas {
+		schemaByVersion[schemas[i].Version] = &schemas[i]
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, mi := range migrations {
+		schema, ok := schemaByVersion[mi.Version]
+		if !ok {
+			statuses = append(statuses, newMigrationStatus(mi.Version, StatePending, nil))
+			continue
+		}
+		statuses = append(statuses, newMigrationStatus(mi.Version, stateOf(schema), schema))
+		delete(schemaByVersion, mi.Version)
+	}
+
+	orphans := make([]MigrationStatus, 0, len(schemaByVersion))
+	for version, schema := range schemaByVersion {
+		orphans = append(orphans, newMigrationStatus(version, StateOrphaned, schema))
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].Version < orphans[j].Version })
+	return append(statuses, orphans...), nil
+}
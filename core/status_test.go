@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatusTestSuite struct {
+	suite.Suite
+	methods  *MockAbstractMethods
+	migrator *BaseMigrator
+}
+
+func (s *StatusTestSuite) SetupTest() {
+	s.methods = &MockAbstractMethods{}
+	s.migrator = &BaseMigrator{
+		BaseMigratorAbstractMethods: s.methods,
+		Migrations: []Migration{
+			{Version: "20240101_initial"},
+			{Version: "20240201_add_users"},
+			{Version: "20240301_add_orders"},
+			{Version: "20240401_add_products"},
+		},
+	}
+}
+
+func (s *StatusTestSuite) TestStatus_ListSchemasError() {
+	errList := fmt.Errorf("list failed")
+	s.methods.On("ListSchemas", mock.Anything).Return(nil, errList).Once()
+
+	_, err := s.migrator.Status(context.Background())
+	s.ErrorIs(err, errList)
+}
+
+func (s *StatusTestSuite) TestStatus_MergesCodeAndStore() {
+	appliedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{
+		{Version: "20231201_removed", Status: Applied, Timestamp: appliedAt},
+		{Version: "20240101_initial", Status: Applied, Timestamp: appliedAt, Duration: time.Second},
+		{Version: "20240201_add_users", Status: Dirty, Timestamp: appliedAt},
+		{Version: "20240301_add_orders", Status: InProgress, Timestamp: appliedAt},
+		{Version: "20231101_dropped", Status: Dirty, Timestamp: appliedAt},
+	}, nil).Once()
+
+	statuses, err := s.migrator.Status(context.Background())
+	s.Require().NoError(err)
+	s.Require().Len(statuses, 6)
+	s.Equal(MigrationStatus{Version: "20240101_initial", State: StateApplied, AppliedAt: &appliedAt, Duration: time.Second}, statuses[0])
+	s.Equal(StateDirty, statuses[1].State)
+	s.Nil(statuses[1].AppliedAt)
+	s.Equal(StateInProgress, statuses[2].State)
+	s.Nil(statuses[2].AppliedAt)
+	s.Equal(MigrationStatus{Version: "20240401_add_products", State: StatePending}, statuses[3])
+	s.Equal("20231101_dropped", statuses[4].Version)
+	s.Equal(StateOrphaned, statuses[4].State)
+	s.Equal("20231201_removed", statuses[5].Version)
+	s.Equal(StateOrphaned, statuses[5].State)
+}
+
+func (s *StatusTestSuite) TestStatus_EmptyStore() {
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{}, nil).Once()
+
+	statuses, err := s.migrator.Status(context.Background())
+	s.Require().NoError(err)
+	s.Require().Len(statuses, 4)
+	for _, status := range statuses {
+		s.Equal(StatePending, status.State)
+		s.Nil(status.AppliedAt)
+	}
+}
+
+func (s *StatusTestSuite) TestStatus_DependencyOrder() {
+	s.migrator.Migrations[1].DependsOn = []string{"20240301_add_orders"}
+	s.methods.On("ListSchemas", mock.Anything).Return([]Schema{}, nil).Once()
+
+	statuses, err := s.migrator.Status(context.Background())
+	s.Require().NoError(err)
+	versions := []string{}
+	for _, status := range statuses {
+		versions = append(versions, status.Version)
+	}
+	s.Equal([]string{"20240101_initial", "20240301_add_orders", "20240201_add_users", "20240401_add_products"}, versions)
+}
+
+func (s *StatusTestSuite) TestStatus_MissingDependency() {
+	s.migrator.Migrations[1].DependsOn = []string{"20231201_removed"}
+
+	_, err := s.migrator.Status(context.Background())
+	s.ErrorContains(err, "depends on version 20231201_removed which does not exist")
+}
+
+func TestStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(StatusTestSuite))
+}
@@ -4,21 +4,35 @@ go 1.25.1
 
 require (
 	github.com/ParteeLabs/gomiger/core v0.0.0-20251015102356-be2ac08da808
+	github.com/ParteeLabs/gomiger/core/metrics v0.0.0-00010101000000-000000000000
 	github.com/ParteeLabs/gomiger/mongomiger v0.0.0-20251015102356-be2ac08da808
 	github.com/urfave/cli/v3 v3.4.1
 	go.mongodb.org/mongo-driver/v2 v2.3.1
 )
 
 require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/golang/snappy v1.0.0 // indirect
 	github.com/klauspost/compress v1.16.7 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/client_golang v1.23.2 // indirect
+	github.com/prometheus/client_model v0.6.2 // indirect
+	github.com/prometheus/common v0.66.1 // indirect
+	github.com/prometheus/procfs v0.16.1 // indirect
 	github.com/stretchr/testify v1.11.1 // indirect
 	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
 	github.com/xdg-go/scram v1.1.2 // indirect
 	github.com/xdg-go/stringprep v1.0.4 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
+	go.yaml.in/yaml/v2 v2.4.2 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
+	google.golang.org/protobuf v1.36.8 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// The metrics module is not published yet, use the one of the repository.
+replace github.com/ParteeLabs/gomiger/core/metrics => ../../core/metrics
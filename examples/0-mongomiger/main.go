@@ -2,6 +2,8 @@ package main
 
 import "example.com/my-app/migrations"
 
+//go:generate go run github.com/ParteeLabs/gomiger/core/cmd/gomiger-gen
+
 func main() {
 	migrations.Run()
 }
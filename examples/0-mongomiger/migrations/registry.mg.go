@@ -0,0 +1,18 @@
+// THIS FILE IS GENERATED BY GOMIGER. PLEASE DO NOT MODIFY IT.
+//
+//nolint:revive
+package migrations
+
+import "github.com/ParteeLabs/gomiger/core"
+
+// registeredMigrations returns the migrations of the package, sorted by version.
+func (m *Migrator) registeredMigrations() []core.Migration {
+	return []core.Migration{
+		{
+			Version:  m.Migration_202510152146_create_users_table_Version(),
+			Up:       m.Migration_202510152146_create_users_table_Up,
+			Down:     m.Migration_202510152146_create_users_table_Down,
+			Checksum: "cd60e5e4c7de86787de3224b028b1c5a4bc5136449220b2d27bd6ee5616dba38",
+		},
+	}
+}
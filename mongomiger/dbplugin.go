@@ -4,6 +4,7 @@ package mongomiger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,23 +23,41 @@ type Mongomiger struct {
 	Db               *mongo.Database
 	schemaStore      string
 	schemaCollection *mongo.Collection
+	lockCollection   *mongo.Collection
+	// transactional runs each migration with its schema update in a transaction (replica sets only).
+	transactional bool
 }
 
 // NewMongomiger creates a new Mongomiger plugin.
 func NewMongomiger(cfg *core.GomigerConfig) *Mongomiger {
 	mongomiger := &Mongomiger{
 		BaseMigrator: &core.BaseMigrator{
-			Migrations: []core.Migration{},
+			Migrations:        []core.Migration{},
+			OutOfOrder:        cfg.OutOfOrder,
+			AllowIrreversible: cfg.AllowIrreversible,
+			Observers:         cfg.Observers,
 		},
-		uri:         cfg.URI,
-		schemaStore: cfg.SchemaStore,
+		uri:           cfg.URI,
+		schemaStore:   cfg.SchemaStore,
+		transactional: cfg.Transactional,
 	}
 	mongomiger.BaseMigratorAbstractMethods = mongomiger
+	mongomiger.Locker = mongomiger
 	return mongomiger
 }
 
 // Connect implements core.DbPlugin.
+// It is a no-op when the plugin is connected, a failed connection is closed.
 func (m *Mongomiger) Connect(ctx context.Context) (err error) {
+	if m.Client != nil {
+		return nil
+	}
+	defer func() {
+		if err != nil && m.Client != nil {
+			_ = m.Client.Disconnect(context.WithoutCancel(ctx))
+			m.Client, m.Db = nil, nil
+		}
+	}()
 	// Parse the connection string to get the database name.
 	connStr, err := connstring.Parse(m.uri)
 	if err != nil {
@@ -50,6 +69,8 @@ func (m *Mongomiger) Connect(ctx context.Context) (err error) {
 	}
 	m.Db = m.Client.Database(connStr.Database)
 	m.schemaCollection = m.Db.Collection(m.schemaStore)
+	// The lock lives in its own collection, so it never shows up as a schema.
+	m.lockCollection = m.Db.Collection(m.schemaStore + "_lock")
 	_, err = m.schemaCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
 		Keys:    bson.M{"version": 1},
 		Options: options.Index().SetUnique(true),
@@ -61,43 +82,100 @@ func (m *Mongomiger) Connect(ctx context.Context) (err error) {
 func (m *Mongomiger) GetSchema(ctx context.Context, version string) (*core.Schema, error) {
 	schema := &core.Schema{}
 	if err := m.schemaCollection.FindOne(ctx, bson.M{"version": version}).Decode(schema); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("failed to get schema at version: %s, Error: %w", version, core.ErrSchemaNotFound)
+		}
 		return nil, fmt.Errorf("failed to get schema: %w", err)
 	}
 	return schema, nil
 }
 
-func (m *Mongomiger) updateSchemaStatus(ctx context.Context, mi core.Migration, status core.SchemaStatus) error {
-	if _, err := m.schemaCollection.UpdateOne(
+// ListSchemas implements core.DbPlugin.
+func (m *Mongomiger) ListSchemas(ctx context.Context) ([]core.Schema, error) {
+	cursor, err := m.schemaCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"version": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	schemas := []core.Schema{}
+	if err := cursor.All(ctx, &schemas); err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	return schemas, nil
+}
+
+// SaveSchema implements core.DbPlugin.
+func (m *Mongomiger) SaveSchema(ctx context.Context, schema core.Schema) error {
+	if _, err := m.schemaCollection.ReplaceOne(
 		ctx,
+		bson.M{"version": schema.Version},
+		schema,
+		options.Replace().SetUpsert(true),
+	); err != nil {
+		return fmt.Errorf("failed to save schema at version: %s, Error: %w", schema.Version, err)
+	}
+	return nil
+}
+
+// DeleteSchema implements core.DbPlugin.
+func (m *Mongomiger) DeleteSchema(ctx context.Context, version string) error {
+	if _, err := m.schemaCollection.DeleteOne(ctx, bson.M{"version": version}); err != nil {
+		return fmt.Errorf("failed to delete schema at version: %s, Error: %w", version, err)
+	}
+	return nil
+}
+
+// updateSchemaStatus records the status of a migration, stamped with its execution and the error of a failed one.
+// It is recorded even if the context is cancelled, e.g. by the timeout which failed the migration.
+func (m *Mongomiger) updateSchemaStatus(ctx context.Context, mi core.Migration, status core.SchemaStatus, cause error, fields ...bson.E) error {
+	schema := core.Schema{Status: status}
+	core.ExecutionFromContext(ctx).Stamp(&schema, cause)
+	set := bson.D{
+		{Key: "status", Value: schema.Status},
+		{Key: "applied_by", Value: schema.AppliedBy},
+		{Key: "gomiger_version", Value: schema.GomigerVersion},
+		{Key: "app_version", Value: schema.AppVersion},
+		{Key: "direction", Value: schema.Direction},
+		{Key: "error", Value: schema.Error},
+	}
+	if _, err := m.schemaCollection.UpdateOne(
+		context.WithoutCancel(ctx),
 		bson.M{"version": mi.Version},
-		bson.M{"$set": bson.M{"status": status}},
+		bson.M{"$set": append(set, fields...)},
 	); err != nil {
-		return fmt.Errorf("failed to update schema status at version: %s, please manually update it with '%s' then try again, Error: %w", mi.Version, status, err)
+		return fmt.Errorf("failed to update schema status at version: %s to '%s', please recover it with the force or retry command, Error: %w", mi.Version, status, err)
 	}
+	core.LoggerFromContext(ctx).Debug("schema status updated", "status", status)
 	return nil
 }
 
 // ApplyMigration implements core.DbPlugin.
 func (m *Mongomiger) ApplyMigration(ctx context.Context, mi core.Migration) error {
+	if m.useTransaction(mi) {
+		return m.applyMigrationInTransaction(ctx, mi)
+	}
 	// Mark the migration as in progress (create a new schema).
+	startedAt := time.Now()
 	schema := &core.Schema{
 		Version:   mi.Version,
 		Status:    core.InProgress,
-		Timestamp: time.Now(),
+		Timestamp: startedAt,
+		Checksum:  mi.Checksum,
 	}
+	core.ExecutionFromContext(ctx).Stamp(schema, nil)
 	if _, err := m.schemaCollection.InsertOne(ctx, schema); err != nil {
 		return fmt.Errorf("failed to apply migration at version: %s, Error: %w", mi.Version, err)
 	}
+	core.LoggerFromContext(ctx).Debug("schema status updated", "status", core.InProgress)
 	// Run the migration.
 	if err := mi.Up(ctx); err != nil {
 		// Mark the migration as dirty.
-		if err := m.updateSchemaStatus(ctx, mi, core.Dirty); err != nil {
+		if err := m.updateSchemaStatus(ctx, mi, core.Dirty, err); err != nil {
 			return err
 		}
 		return fmt.Errorf("failed to apply migration %s: %w", mi.Version, err)
 	}
 	// Mark the migration as applied.
-	if err := m.updateSchemaStatus(ctx, mi, core.Applied); err != nil {
+	if err := m.updateSchemaStatus(ctx, mi, core.Applied, nil, bson.E{Key: "duration", Value: time.Since(startedAt)}); err != nil {
 		return err
 	}
 	return nil
@@ -105,16 +183,20 @@ func (m *Mongomiger) ApplyMigration(ctx context.Context, mi core.Migration) erro
 
 // RevertMigration implements core.DbPlugin.
 func (m *Mongomiger) RevertMigration(ctx context.Context, mi core.Migration) error {
+	if m.useTransaction(mi) {
+		return m.revertMigrationInTransaction(ctx, mi)
+	}
 	if err := mi.Down(ctx); err != nil {
 		// Mark the migration as dirty.
-		if err := m.updateSchemaStatus(ctx, mi, core.Dirty); err != nil {
+		if err := m.updateSchemaStatus(ctx, mi, core.Dirty, err); err != nil {
 			return err
 		}
 		return fmt.Errorf("failed to revert migration %s: %w", mi.Version, err)
 	}
 	// Delete the schema.
 	if _, err := m.schemaCollection.DeleteOne(ctx, bson.M{"version": mi.Version}); err != nil {
-		return fmt.Errorf("failed to delete schema at version: %s, please manually delete it, Error: %w", mi.Version, err)
+		return fmt.Errorf("failed to delete schema at version: %s, please recover it with 'force %s --status pending', Error: %w", mi.Version, mi.Version, err)
 	}
+	core.LoggerFromContext(ctx).Debug("schema deleted")
 	return nil
 }
@@ -1,12 +1,15 @@
 package mongomiger
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
 	"testing"
 	"time"
 
 	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/plugintest"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -72,15 +75,6 @@ func (s *MongomigerTestSuite) TestMongomiger_Connect_Unavailable() {
 	s.Require().Error(err)
 }
 
-func (s *MongomigerTestSuite) TestMongomiger_GetSchema_NotFound() {
-	// Seed the database.
-	_, err := s.mongomiger.schemaCollection.InsertOne(s.ctx, bson.M{"version": "1.0.0", "status": "applied"})
-	s.Require().NoError(err)
-	// Try to get a non-existent schema.
-	_, err = s.mongomiger.GetSchema(s.ctx, "2.0.0")
-	s.Require().Error(err)
-}
-
 func (s *MongomigerTestSuite) TestMongomiger_GetSchema_Found() {
 	// Seed the database.
 	expectedSchema := &core.Schema{
@@ -96,6 +90,24 @@ func (s *MongomigerTestSuite) TestMongomiger_GetSchema_Found() {
 	s.Require().Equal(expectedSchema.Status, schema.Status)
 }
 
+func (s *MongomigerTestSuite) TestMongomiger_GetSchema_LegacyDocument() {
+	// A schema document recorded before the execution metadata.
+	_, err := s.mongomiger.schemaCollection.InsertOne(s.ctx, bson.M{"version": "1.0.0", "timestamp": time.Now(), "status": core.Applied})
+	s.Require().NoError(err)
+
+	schema, err := s.mongomiger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+	s.Require().Empty(schema.AppliedBy)
+	s.Require().Empty(schema.Direction)
+	schema.AppliedBy, schema.Direction = "deploy@ci-runner-1", core.DirectionUp
+	s.Require().NoError(s.mongomiger.SaveSchema(s.ctx, *schema))
+	schema, err = s.mongomiger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal("deploy@ci-runner-1", schema.AppliedBy)
+	s.Require().Equal(core.DirectionUp, schema.Direction)
+}
+
 func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_UpdateStatusError() {
 	// Use a separate instance to avoid affecting suite state
 	tempMongomiger := NewMongomiger(s.config)
@@ -121,37 +133,6 @@ func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_UpdateStatusError()
 	s.ErrorContains(err, "failed to update schema status")
 }
 
-func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_DuplicateVersion() {
-	schema := &core.Schema{
-		Version: "1.0.0",
-		Status:  core.Applied,
-	}
-	_, err := s.mongomiger.schemaCollection.InsertOne(s.ctx, bson.M{"version": schema.Version, "status": schema.Status})
-	s.Require().NoError(err)
-	// Try to apply a migration with the same version.
-	migration := core.Migration{
-		Version: "1.0.0",
-		Up:      func(ctx context.Context) error { return nil },
-	}
-	err = s.mongomiger.ApplyMigration(s.ctx, migration)
-	s.Require().Error(err)
-	s.ErrorContains(err, "failed to apply migration at version")
-}
-
-func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_FailureMarksDirty() {
-	migration := core.Migration{
-		Version: "1.0.0",
-		Up:      func(ctx context.Context) error { return fmt.Errorf("migration failed") },
-	}
-	err := s.mongomiger.ApplyMigration(s.ctx, migration)
-	s.Require().Error(err)
-	s.ErrorContains(err, "failed to apply migration")
-	// Verify that the schema status is set to Dirty.
-	schema, err := s.mongomiger.GetSchema(s.ctx, "1.0.0")
-	s.Require().NoError(err)
-	s.Require().Equal(core.Dirty, schema.Status)
-}
-
 func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_UpdateSchemaToDirtyError() {
 	// Use a separate instance to avoid affecting suite state
 	tempMongomiger := NewMongomiger(s.config)
@@ -175,40 +156,24 @@ func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_UpdateSchemaToDirtyE
 	s.ErrorContains(err, "failed to update schema status")
 }
 
-func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_Success() {
+func (s *MongomigerTestSuite) TestMongomiger_Validate() {
 	migration := core.Migration{
-		Version: "1.0.0",
-		Up:      func(ctx context.Context) error { return nil },
+		Version:  "1.0.0",
+		Up:       func(ctx context.Context) error { return nil },
+		Checksum: "checksum-v1",
 	}
 	err := s.mongomiger.ApplyMigration(s.ctx, migration)
 	s.Require().NoError(err)
-	// Verify that the schema status is set to Applied.
+	// Verify that the checksum is recorded.
 	schema, err := s.mongomiger.GetSchema(s.ctx, migration.Version)
 	s.Require().NoError(err)
-	s.Require().Equal(core.Applied, schema.Status)
-}
-
-func (s *MongomigerTestSuite) TestMongomiger_RevertMigration_FailureMarksDirty() {
-	schema := &core.Schema{
-		Version:   "1.0.0",
-		Status:    core.Applied,
-		Timestamp: time.Now(),
-	}
-	_, err := s.mongomiger.schemaCollection.InsertOne(s.ctx, schema)
-	s.Require().NoError(err)
-
-	migration := core.Migration{
-		Version: "1.0.0",
-		Down:    func(ctx context.Context) error { return fmt.Errorf("revert failed") },
-	}
-
-	err = s.mongomiger.RevertMigration(s.ctx, migration)
-	s.Require().Error(err)
-	s.ErrorContains(err, "failed to revert migration")
-	// Verify that the schema status is set to Dirty.
-	resultSchema, err := s.mongomiger.GetSchema(s.ctx, "1.0.0")
+	s.Require().Equal("checksum-v1", schema.Checksum)
+	// Edit the migration code.
+	migration.Checksum = "checksum-v2"
+	s.mongomiger.Migrations = []core.Migration{migration}
+	mismatches, err := s.mongomiger.Validate(s.ctx)
 	s.Require().NoError(err)
-	s.Require().Equal(core.Dirty, resultSchema.Status)
+	s.Require().Equal([]core.ChecksumMismatch{{Version: "1.0.0", Recorded: "checksum-v1", Current: "checksum-v2"}}, mismatches)
 }
 
 func (s *MongomigerTestSuite) TestMongomiger_RevertMigration_UpdateSchemaToDirtyError() {
@@ -274,25 +239,109 @@ func (s *MongomigerTestSuite) TestMongomiger_RevertMigration_DeleteSchemaError()
 	s.ErrorContains(err, "failed to delete schema at version")
 }
 
-func (s *MongomigerTestSuite) TestMongomiger_RevertMigration_Success() {
-	// First, apply a migration to have something to revert.
-	migration := core.Migration{
-		Version: "1.0.0",
-		Up:      func(ctx context.Context) error { return nil },
+func (s *MongomigerTestSuite) TestMongomiger_Up_FreshDatabase() {
+	applied := []string{}
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		s.mongomiger.Migrations = append(s.mongomiger.Migrations, core.Migration{
+			Version: version,
+			Up: func(ctx context.Context) error {
+				applied = append(applied, version)
+				return nil
+			},
+		})
 	}
-	err := s.mongomiger.ApplyMigration(s.ctx, migration)
+	err := s.mongomiger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"1.0.0", "2.0.0"}, applied)
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_Status() {
+	s.mongomiger.Migrations = []core.Migration{
+		{Version: "1.0.0", Up: func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}},
+		{Version: "2.0.0"},
+	}
+	err := s.mongomiger.ApplyMigration(s.ctx, s.mongomiger.Migrations[0])
+	s.Require().NoError(err)
+	_, err = s.mongomiger.schemaCollection.InsertOne(s.ctx, &core.Schema{Version: "0.1.0", Status: core.Applied, Timestamp: time.Now()})
 	s.Require().NoError(err)
 
-	// Now, revert the migration.
-	migration.Down = func(ctx context.Context) error { return nil }
-	err = s.mongomiger.RevertMigration(s.ctx, migration)
+	statuses, err := s.mongomiger.Status(s.ctx)
 	s.Require().NoError(err)
+	s.Require().Len(statuses, 3)
+	s.Require().Equal(core.StateApplied, statuses[0].State)
+	s.Require().NotNil(statuses[0].AppliedAt)
+	s.Require().GreaterOrEqual(statuses[0].Duration, 10*time.Millisecond)
+	s.Require().Equal(core.StatePending, statuses[1].State)
+	s.Require().Equal(core.StateOrphaned, statuses[2].State)
+}
 
-	// Verify that the schema is deleted.
-	_, err = s.mongomiger.GetSchema(s.ctx, migration.Version)
+func (s *MongomigerTestSuite) TestMongomiger_Retry() {
+	attempts := 0
+	s.mongomiger.Migrations = []core.Migration{{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return fmt.Errorf("migration failed")
+			}
+			return nil
+		},
+	}}
+	err := s.mongomiger.Up(s.ctx, "")
 	s.Require().Error(err)
+	// Dirty migrations are not applied again by Up.
+	err = s.mongomiger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal(1, attempts)
+
+	err = s.mongomiger.Retry(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(2, attempts)
+	schema, err := s.mongomiger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_Up_LogsStatusTransitions() {
+	var logs bytes.Buffer
+	s.mongomiger.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	s.mongomiger.Migrations = []core.Migration{{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			core.LoggerFromContext(ctx).Info("creating the users collection")
+			return nil
+		},
+	}}
+	err := s.mongomiger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Contains(logs.String(), `msg="creating the users collection" version=1.0.0 direction=up`)
+	s.Require().Contains(logs.String(), `msg="schema status updated" version=1.0.0 direction=up status=in_progress`)
+	s.Require().Contains(logs.String(), `msg="schema status updated" version=1.0.0 direction=up status=applied`)
 }
 
 func TestMongomigerTestSuite(t *testing.T) {
 	suite.Run(t, new(MongomigerTestSuite))
 }
+
+func TestMongomiger_Conformance(t *testing.T) {
+	// The conformance plugins share a dedicated database, dropped after each test.
+	plugintest.Run(t, func(t *testing.T) core.BaseMigratorAbstractMethods {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		mongomiger := NewMongomiger(&core.GomigerConfig{
+			URI:         "mongodb://localhost:27017/mongomiger_plugintest",
+			SchemaStore: "schema_migrations",
+		})
+		if err := mongomiger.Connect(ctx); err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = mongomiger.Db.Drop(context.Background())
+			_ = mongomiger.Client.Disconnect(context.Background())
+		})
+		return mongomiger
+	})
+}
@@ -0,0 +1,88 @@
+package mongomiger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// lockID is the _id of the single lock document in the lock collection.
+const lockID = "gomiger"
+
+var _ core.Locker = (*Mongomiger)(nil)
+
+// AcquireLock implements core.Locker.
+// The lock document is upserted only when it is free, expired or already owned by the owner,
+// otherwise the upsert hits the _id unique index and the lock is reported as held.
+func (m *Mongomiger) AcquireLock(ctx context.Context, owner string, ttl time.Duration) error {
+	now := time.Now()
+	_, err := m.lockCollection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id": lockID,
+			"$or": bson.A{
+				bson.M{"owner": owner},
+				bson.M{"expires_at": bson.M{"$lte": now}},
+			},
+		},
+		bson.M{"$set": bson.M{"owner": owner, "acquired_at": now, "expires_at": now.Add(ttl)}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return core.ErrLockHeld
+	}
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock for owner: %s, Error: %w", owner, err)
+	}
+	return nil
+}
+
+// RefreshLock implements core.Locker.
+func (m *Mongomiger) RefreshLock(ctx context.Context, owner string, ttl time.Duration) error {
+	result, err := m.lockCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": lockID, "owner": owner},
+		bson.M{"$set": bson.M{"expires_at": time.Now().Add(ttl)}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock for owner: %s, Error: %w", owner, err)
+	}
+	if result.MatchedCount == 0 {
+		return core.ErrLockLost
+	}
+	return nil
+}
+
+// ReleaseLock implements core.Locker.
+func (m *Mongomiger) ReleaseLock(ctx context.Context, owner string) error {
+	if _, err := m.lockCollection.DeleteOne(ctx, bson.M{"_id": lockID, "owner": owner}); err != nil {
+		return fmt.Errorf("failed to release lock for owner: %s, Error: %w", owner, err)
+	}
+	return nil
+}
+
+// GetLock implements core.Locker.
+func (m *Mongomiger) GetLock(ctx context.Context) (*core.Lock, error) {
+	lock := &core.Lock{}
+	if err := m.lockCollection.FindOne(ctx, bson.M{"_id": lockID}).Decode(lock); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get lock: %w", err)
+	}
+	return lock, nil
+}
+
+// ForceReleaseLock implements core.Locker.
+func (m *Mongomiger) ForceReleaseLock(ctx context.Context) error {
+	if _, err := m.lockCollection.DeleteOne(ctx, bson.M{"_id": lockID}); err != nil {
+		return fmt.Errorf("failed to force release lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package mongomiger
+
+import (
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+func (s *MongomigerTestSuite) TestMongomiger_AcquireLock_Expired() {
+	err := s.mongomiger.AcquireLock(s.ctx, "owner-1", -time.Second)
+	s.Require().NoError(err)
+	// Take over the expired lock.
+	err = s.mongomiger.AcquireLock(s.ctx, "owner-2", time.Minute)
+	s.Require().NoError(err)
+	lock, err := s.mongomiger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal("owner-2", lock.Owner)
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_RefreshLock() {
+	err := s.mongomiger.AcquireLock(s.ctx, "owner-1", time.Second)
+	s.Require().NoError(err)
+	err = s.mongomiger.RefreshLock(s.ctx, "owner-1", time.Hour)
+	s.Require().NoError(err)
+	lock, err := s.mongomiger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().True(lock.ExpiresAt.After(time.Now().Add(time.Minute)))
+	// A non-owner cannot refresh the lock.
+	err = s.mongomiger.RefreshLock(s.ctx, "owner-2", time.Hour)
+	s.Require().ErrorIs(err, core.ErrLockLost)
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_ForceUnlock() {
+	err := s.mongomiger.AcquireLock(s.ctx, "crashed-owner", time.Hour)
+	s.Require().NoError(err)
+	err = s.mongomiger.ForceUnlock(s.ctx)
+	s.Require().NoError(err)
+	lock, err := s.mongomiger.LockStatus(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(lock)
+}
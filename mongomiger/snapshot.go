@@ -0,0 +1,45 @@
+package mongomiger
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Snapshot implements gomigertest.Snapshotter.
+// The state is the options of the collections & views of the database (e.g. their validator), and their indexes,
+// by type & name. The schema & lock collections are excluded.
+func (m *Mongomiger) Snapshot(ctx context.Context) (map[string]string, error) {
+	cursor, err := m.Db.ListCollections(ctx, bson.M{"name": bson.M{"$nin": bson.A{m.schemaStore, m.schemaStore + "_lock"}}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+	var collections []struct {
+		Name    string   `bson:"name"`
+		Type    string   `bson:"type"`
+		Options bson.Raw `bson:"options"`
+	}
+	if err := cursor.All(ctx, &collections); err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+	snapshot := map[string]string{}
+	for _, collection := range collections {
+		snapshot[collection.Type+" "+collection.Name] = collection.Options.String()
+		if collection.Type != "collection" {
+			continue
+		}
+		cursor, err := m.Db.Collection(collection.Name).Indexes().List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot the indexes of collection: %s, Error: %w", collection.Name, err)
+		}
+		var indexes []bson.Raw
+		if err := cursor.All(ctx, &indexes); err != nil {
+			return nil, fmt.Errorf("failed to snapshot the indexes of collection: %s, Error: %w", collection.Name, err)
+		}
+		for _, index := range indexes {
+			snapshot["index "+collection.Name+"."+index.Lookup("name").StringValue()] = index.String()
+		}
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,68 @@
+package mongomiger
+
+import (
+	"context"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/gomigertest"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var _ gomigertest.Snapshotter = (*Mongomiger)(nil)
+
+// createUsers returns a migration which creates the users collection with a validator & an index.
+// The down of an irreversible migration leaves them.
+func (s *MongomigerTestSuite) createUsers(reversible bool) core.Migration {
+	return core.Migration{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			validator := bson.M{"$jsonSchema": bson.M{"required": bson.A{"email"}}}
+			if err := s.mongomiger.Db.CreateCollection(ctx, "users", options.CreateCollection().SetValidator(validator)); err != nil {
+				return err
+			}
+			_, err := s.mongomiger.Db.Collection("users").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetName("users_email").SetUnique(true),
+			})
+			return err
+		},
+		Down: func(ctx context.Context) error {
+			if reversible {
+				return s.mongomiger.Db.Collection("users").Drop(ctx)
+			}
+			// Only drop the validator, the collection & its index are left.
+			return s.mongomiger.Db.RunCommand(ctx, bson.D{{Key: "collMod", Value: "users"}, {Key: "validator", Value: bson.M{}}}).Err()
+		},
+	}
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_Snapshot() {
+	err := s.createUsers(true).Up(s.ctx)
+	s.Require().NoError(err)
+
+	snapshot, err := s.mongomiger.Snapshot(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Contains(snapshot["collection users"], "$jsonSchema")
+	s.Require().Contains(snapshot, "index users._id_")
+	s.Require().Contains(snapshot["index users.users_email"], `"unique": true`)
+	// The schema & lock collections are excluded.
+	s.Require().NotContains(snapshot, "collection schema_migrations")
+	s.Require().NotContains(snapshot, "collection schema_migrations_lock")
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_Roundtrip() {
+	s.mongomiger.Migrations = []core.Migration{s.createUsers(true)}
+	err := gomigertest.Verify(s.ctx, s.mongomiger, s.mongomiger)
+	s.Require().NoError(err)
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_Roundtrip_Irreversible() {
+	s.mongomiger.Migrations = []core.Migration{s.createUsers(false)}
+	err := gomigertest.Verify(s.ctx, s.mongomiger, s.mongomiger)
+	var irreversible *gomigertest.IrreversibleError
+	s.Require().ErrorAs(err, &irreversible)
+	s.Require().Equal("1.0.0", irreversible.Version)
+	s.Require().Equal(gomigertest.StageDown, irreversible.Stage)
+}
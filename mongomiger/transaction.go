@@ -0,0 +1,77 @@
+package mongomiger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// useTransaction tells if a migration runs in a transaction.
+func (m *Mongomiger) useTransaction(mi core.Migration) bool {
+	return m.transactional && !mi.DisableTransaction
+}
+
+// inTransaction runs fn in a transaction of a new session. The context passed to fn carries the session,
+// so every operation using it is part of the transaction.
+// The driver retries fn on transient transaction errors, so it may run more than once.
+func (m *Mongomiger) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := m.Client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// applyMigrationInTransaction runs the migration and records its schema in a single transaction.
+// A failed migration is rolled back and stays pending, it is never marked as dirty.
+func (m *Mongomiger) applyMigrationInTransaction(ctx context.Context, mi core.Migration) error {
+	if err := m.inTransaction(ctx, func(ctx context.Context) error {
+		startedAt := time.Now()
+		if err := mi.Up(ctx); err != nil {
+			return err
+		}
+		schema := &core.Schema{
+			Version:   mi.Version,
+			Status:    core.Applied,
+			Timestamp: startedAt,
+			Duration:  time.Since(startedAt),
+			Checksum:  mi.Checksum,
+		}
+		core.ExecutionFromContext(ctx).Stamp(schema, nil)
+		if _, err := m.schemaCollection.InsertOne(ctx, schema); err != nil {
+			return fmt.Errorf("failed to insert schema at version: %s, Error: %w", mi.Version, err)
+		}
+		return nil
+	}); err != nil {
+		core.LoggerFromContext(ctx).Warn("transaction rolled back, the migration stays pending", "error", err)
+		return fmt.Errorf("failed to apply migration %s, the transaction is rolled back: %w", mi.Version, err)
+	}
+	core.LoggerFromContext(ctx).Debug("schema status updated", "status", core.Applied)
+	return nil
+}
+
+// revertMigrationInTransaction reverts the migration and deletes its schema in a single transaction.
+// A failed revert is rolled back and the migration stays applied.
+func (m *Mongomiger) revertMigrationInTransaction(ctx context.Context, mi core.Migration) error {
+	if err := m.inTransaction(ctx, func(ctx context.Context) error {
+		if err := mi.Down(ctx); err != nil {
+			return err
+		}
+		if _, err := m.schemaCollection.DeleteOne(ctx, bson.M{"version": mi.Version}); err != nil {
+			return fmt.Errorf("failed to delete schema at version: %s, Error: %w", mi.Version, err)
+		}
+		return nil
+	}); err != nil {
+		core.LoggerFromContext(ctx).Warn("transaction rolled back, the migration stays applied", "error", err)
+		return fmt.Errorf("failed to revert migration %s, the transaction is rolled back: %w", mi.Version, err)
+	}
+	core.LoggerFromContext(ctx).Debug("schema deleted")
+	return nil
+}
@@ -0,0 +1,102 @@
+package mongomiger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// requireReplicaSet skips the test when the server does not support transactions.
+func (s *MongomigerTestSuite) requireReplicaSet() {
+	var hello bson.M
+	err := s.mongomiger.Db.RunCommand(s.ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	s.Require().NoError(err)
+	if _, ok := hello["setName"]; !ok {
+		s.T().Skip("transactions require a replica set")
+	}
+	s.mongomiger.transactional = true
+	// Collections cannot be created implicitly in a transaction on old servers.
+	s.Require().NoError(s.mongomiger.Db.CreateCollection(s.ctx, "users"))
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_Transaction() {
+	s.requireReplicaSet()
+	migration := core.Migration{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			_, err := s.mongomiger.Db.Collection("users").InsertOne(ctx, bson.M{"name": "alice"})
+			return err
+		},
+	}
+	err := s.mongomiger.ApplyMigration(s.ctx, migration)
+	s.Require().NoError(err)
+	// Verify the data and the schema.
+	count, err := s.mongomiger.Db.Collection("users").CountDocuments(s.ctx, bson.M{})
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, count)
+	schema, err := s.mongomiger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_TransactionRollback() {
+	s.requireReplicaSet()
+	migration := core.Migration{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			if _, err := s.mongomiger.Db.Collection("users").InsertOne(ctx, bson.M{"name": "alice"}); err != nil {
+				return err
+			}
+			return fmt.Errorf("migration failed")
+		},
+	}
+	err := s.mongomiger.ApplyMigration(s.ctx, migration)
+	s.Require().ErrorContains(err, "the transaction is rolled back")
+	// Verify the data is rolled back and the migration is still pending.
+	count, err := s.mongomiger.Db.Collection("users").CountDocuments(s.ctx, bson.M{})
+	s.Require().NoError(err)
+	s.Require().Zero(count)
+	_, err = s.mongomiger.GetSchema(s.ctx, migration.Version)
+	s.Require().ErrorIs(err, core.ErrSchemaNotFound)
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_ApplyMigration_DisableTransaction() {
+	s.requireReplicaSet()
+	migration := core.Migration{
+		Version:            "1.0.0",
+		Up:                 func(ctx context.Context) error { return fmt.Errorf("migration failed") },
+		DisableTransaction: true,
+	}
+	err := s.mongomiger.ApplyMigration(s.ctx, migration)
+	s.Require().Error(err)
+	// Without transaction, the failed migration is marked as dirty.
+	schema, err := s.mongomiger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+}
+
+func (s *MongomigerTestSuite) TestMongomiger_RevertMigration_TransactionRollback() {
+	s.requireReplicaSet()
+	_, err := s.mongomiger.schemaCollection.InsertOne(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied})
+	s.Require().NoError(err)
+	migration := core.Migration{
+		Version: "1.0.0",
+		Down: func(ctx context.Context) error {
+			if _, err := s.mongomiger.Db.Collection("users").InsertOne(ctx, bson.M{"name": "bob"}); err != nil {
+				return err
+			}
+			return fmt.Errorf("revert failed")
+		},
+	}
+	err = s.mongomiger.RevertMigration(s.ctx, migration)
+	s.Require().ErrorContains(err, "the transaction is rolled back")
+	// Verify the migration is still applied.
+	count, err := s.mongomiger.Db.Collection("users").CountDocuments(s.ctx, bson.M{})
+	s.Require().NoError(err)
+	s.Require().Zero(count)
+	schema, err := s.mongomiger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
@@ -0,0 +1,260 @@
+// Package mysqlminger provides MySQL & MariaDB implementation of the Gomiger interface.
+// Extended from core.BaseMigrator.
+package mysqlminger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Mysqlminger implements core.DbPlugin for MySQL & MariaDB.
+// MySQL commits DDL statements implicitly, so the migrations do not run in transactions:
+// a failed migration is marked as dirty, as its statements may be partially applied.
+type Mysqlminger struct {
+	*core.BaseMigrator
+	uri         string
+	DB          *sql.DB
+	dbName      string
+	schemaStore string
+
+	// lockMu guards the connection which holds the GET_LOCK lock.
+	lockMu    sync.Mutex
+	lockConn  *sql.Conn
+	lockOwner string
+}
+
+// NewMysqlminger creates a new Mysqlminger plugin.
+// The URI is the data source name of the go-sql-driver/mysql driver, e.g. "user:password@tcp(localhost:3306)/app".
+func NewMysqlminger(cfg *core.GomigerConfig) *Mysqlminger {
+	mysqlminger := &Mysqlminger{
+		BaseMigrator: &core.BaseMigrator{
+			Migrations:        []core.Migration{},
+			OutOfOrder:        cfg.OutOfOrder,
+			AllowIrreversible: cfg.AllowIrreversible,
+			Observers:         cfg.Observers,
+		},
+		uri:         cfg.URI,
+		schemaStore: cfg.SchemaStore,
+	}
+	mysqlminger.BaseMigratorAbstractMethods = mysqlminger
+	mysqlminger.Locker = mysqlminger
+	return mysqlminger
+}
+
+// quoteIdent quotes an identifier, e.g. the schema table name.
+func quoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Connect implements core.DbPlugin.
+// It is a no-op when the plugin is connected, a failed connection is closed.
+func (m *Mysqlminger) Connect(ctx context.Context) (err error) {
+	if m.DB != nil {
+		return nil
+	}
+	defer func() {
+		if err != nil && m.DB != nil {
+			_ = m.DB.Close()
+			m.DB = nil
+		}
+	}()
+	cfg, err := mysql.ParseDSN(m.uri)
+	if err != nil {
+		return fmt.Errorf("failed to parse the connection string: %w", err)
+	}
+	// The schema timestamps are scanned to time.Time.
+	cfg.ParseTime = true
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	m.DB = sql.OpenDB(connector)
+	m.dbName = cfg.DBName
+	if err = m.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	// A TEXT column has no default value before MySQL 8.0.13, the rows are always inserted with an error.
+	if _, err = m.DB.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version VARCHAR(255) NOT NULL PRIMARY KEY,
+		timestamp DATETIME(6) NOT NULL,
+		status VARCHAR(32) NOT NULL,
+		duration BIGINT NOT NULL DEFAULT 0,
+		checksum VARCHAR(255) NOT NULL DEFAULT '',
+		baselined BOOLEAN NOT NULL DEFAULT FALSE,
+		applied_by VARCHAR(255) NOT NULL DEFAULT '',
+		gomiger_version VARCHAR(255) NOT NULL DEFAULT '',
+		app_version VARCHAR(255) NOT NULL DEFAULT '',
+		direction VARCHAR(32) NOT NULL DEFAULT '',
+		error TEXT NOT NULL
+	)`, quoteIdent(m.schemaStore))); err != nil {
+		return fmt.Errorf("failed to create schema table: %s, Error: %w", m.schemaStore, err)
+	}
+	// The lock table only tells who holds the lock, the lock itself is GET_LOCK.
+	if _, err = m.DB.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id TINYINT NOT NULL PRIMARY KEY,
+		owner VARCHAR(255) NOT NULL,
+		acquired_at DATETIME(6) NOT NULL
+	)`, quoteIdent(m.schemaStore+"_lock"))); err != nil {
+		return fmt.Errorf("failed to create lock table: %s_lock, Error: %w", m.schemaStore, err)
+	}
+	return nil
+}
+
+// schemaColumns are the columns scanned by scanSchema.
+const schemaColumns = "version, timestamp, status, duration, checksum, baselined, applied_by, gomiger_version, app_version, direction, error"
+
+// schemaValues are the values of the schemaColumns.
+func schemaValues(schema core.Schema) []any {
+	return []any{
+		schema.Version, schema.Timestamp, string(schema.Status), int64(schema.Duration), schema.Checksum, schema.Baselined,
+		schema.AppliedBy, schema.GomigerVersion, schema.AppVersion, string(schema.Direction), schema.Error,
+	}
+}
+
+// scanner is a *sql.Row or *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSchema(row scanner) (*core.Schema, error) {
+	var (
+		schema    core.Schema
+		status    string
+		duration  int64
+		direction string
+	)
+	if err := row.Scan(
+		&schema.Version, &schema.Timestamp, &status, &duration, &schema.Checksum, &schema.Baselined,
+		&schema.AppliedBy, &schema.GomigerVersion, &schema.AppVersion, &direction, &schema.Error,
+	); err != nil {
+		return nil, err
+	}
+	schema.Status = core.SchemaStatus(status)
+	schema.Duration = time.Duration(duration)
+	schema.Direction = core.Direction(direction)
+	return &schema, nil
+}
+
+// GetSchema implements core.DbPlugin.
+func (m *Mysqlminger) GetSchema(ctx context.Context, version string) (*core.Schema, error) {
+	schema, err := scanSchema(m.DB.QueryRowContext(
+		ctx,
+		fmt.Sprintf("SELECT %s FROM %s WHERE version = ?", schemaColumns, quoteIdent(m.schemaStore)),
+		version,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get schema at version: %s, Error: %w", version, core.ErrSchemaNotFound)
+		}
+		return nil, fmt.Errorf("failed to get schema: %w", err)
+	}
+	return schema, nil
+}
+
+// ListSchemas implements core.DbPlugin.
+func (m *Mysqlminger) ListSchemas(ctx context.Context) ([]core.Schema, error) {
+	rows, err := m.DB.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s ORDER BY version", schemaColumns, quoteIdent(m.schemaStore)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+	schemas := []core.Schema{}
+	for rows.Next() {
+		schema, err := scanSchema(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list schemas: %w", err)
+		}
+		schemas = append(schemas, *schema)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	return schemas, nil
+}
+
+// SaveSchema implements core.DbPlugin.
+func (m *Mysqlminger) SaveSchema(ctx context.Context, schema core.Schema) error {
+	if _, err := m.DB.ExecContext(
+		ctx,
+		fmt.Sprintf("REPLACE INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", quoteIdent(m.schemaStore), schemaColumns),
+		schemaValues(schema)...,
+	); err != nil {
+		return fmt.Errorf("failed to save schema at version: %s, Error: %w", schema.Version, err)
+	}
+	return nil
+}
+
+// DeleteSchema implements core.DbPlugin.
+func (m *Mysqlminger) DeleteSchema(ctx context.Context, version string) error {
+	if _, err := m.DB.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE version = ?", quoteIdent(m.schemaStore)), version); err != nil {
+		return fmt.Errorf("failed to delete schema at version: %s, Error: %w", version, err)
+	}
+	return nil
+}
+
+// updateSchemaStatus records the status of a migration, stamped with its execution and the error of a failed one.
+// It is recorded even if the context is cancelled, e.g. by the timeout which failed the migration.
+func (m *Mysqlminger) updateSchemaStatus(ctx context.Context, mi core.Migration, status core.SchemaStatus, duration time.Duration, cause error) error {
+	schema := core.Schema{Status: status, Duration: duration}
+	core.ExecutionFromContext(ctx).Stamp(&schema, cause)
+	if _, err := m.DB.ExecContext(
+		context.WithoutCancel(ctx),
+		fmt.Sprintf(
+			"UPDATE %s SET status = ?, duration = ?, applied_by = ?, gomiger_version = ?, app_version = ?, direction = ?, error = ? WHERE version = ?",
+			quoteIdent(m.schemaStore),
+		),
+		string(schema.Status), int64(schema.Duration), schema.AppliedBy, schema.GomigerVersion, schema.AppVersion, string(schema.Direction), schema.Error, mi.Version,
+	); err != nil {
+		return fmt.Errorf("failed to update schema status at version: %s to '%s', please recover it with the force or retry command, Error: %w", mi.Version, status, err)
+	}
+	return nil
+}
+
+// ApplyMigration implements core.DbPlugin.
+func (m *Mysqlminger) ApplyMigration(ctx context.Context, mi core.Migration) error {
+	// Mark the migration as in progress (create a new schema).
+	startedAt := time.Now()
+	schema := core.Schema{Version: mi.Version, Timestamp: startedAt, Status: core.InProgress, Checksum: mi.Checksum}
+	core.ExecutionFromContext(ctx).Stamp(&schema, nil)
+	if _, err := m.DB.ExecContext(
+		ctx,
+		fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", quoteIdent(m.schemaStore), schemaColumns),
+		schemaValues(schema)...,
+	); err != nil {
+		return fmt.Errorf("failed to apply migration at version: %s, Error: %w", mi.Version, err)
+	}
+	// Run the migration.
+	if err := mi.Up(ctx); err != nil {
+		// Mark the migration as dirty, the DDL statements which ran before the failure are committed.
+		if err := m.updateSchemaStatus(ctx, mi, core.Dirty, 0, err); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to apply migration %s, its statements may be partially applied as MySQL DDL is not transactional: %w", mi.Version, err)
+	}
+	// Mark the migration as applied.
+	return m.updateSchemaStatus(ctx, mi, core.Applied, time.Since(startedAt), nil)
+}
+
+// RevertMigration implements core.DbPlugin.
+func (m *Mysqlminger) RevertMigration(ctx context.Context, mi core.Migration) error {
+	if err := mi.Down(ctx); err != nil {
+		// Mark the migration as dirty, the DDL statements which ran before the failure are committed.
+		if err := m.updateSchemaStatus(ctx, mi, core.Dirty, 0, err); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to revert migration %s, its statements may be partially applied as MySQL DDL is not transactional: %w", mi.Version, err)
+	}
+	// Delete the schema.
+	if err := m.DeleteSchema(ctx, mi.Version); err != nil {
+		return fmt.Errorf("%w, please recover it with 'force %s --status pending'", err, mi.Version)
+	}
+	return nil
+}
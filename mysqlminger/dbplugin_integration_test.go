@@ -0,0 +1,307 @@
+package mysqlminger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/plugintest"
+	"github.com/stretchr/testify/suite"
+)
+
+// startMysqld starts a throwaway MySQL or MariaDB server with the mysqld binary of the PATH, and returns the URI of a test database.
+// MYSQLMINGER_TEST_URI runs the tests against an existing server instead.
+// The tests are skipped when none of them is available.
+func startMysqld(t *testing.T) string {
+	if uri := os.Getenv("MYSQLMINGER_TEST_URI"); uri != "" {
+		return uri
+	}
+	mysqld, err := exec.LookPath("mysqld")
+	if err != nil {
+		t.Skip("mysqld is not in the PATH, install MySQL or set MYSQLMINGER_TEST_URI")
+	}
+	current, err := user.Current()
+	if err != nil {
+		t.Fatalf("Failed to get the current user: %v", err)
+	}
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data")
+	socket := filepath.Join(dir, "mysqld.sock")
+	// MariaDB has its own installer, MySQL initializes the data folder with mysqld.
+	install := exec.Command(mysqld, "--no-defaults", "--initialize-insecure", "--datadir="+data, "--user="+current.Username)
+	if installDB, err := exec.LookPath("mariadb-install-db"); err == nil {
+		install = exec.Command(installDB, "--no-defaults", "--datadir="+data, "--user="+current.Username, "--auth-root-authentication-method=normal")
+	}
+	if out, err := install.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to init the mysqld data folder: %v\n%s", err, out)
+	}
+	server := exec.Command(mysqld, "--no-defaults", "--datadir="+data, "--socket="+socket, "--skip-networking",
+		"--user="+current.Username, "--log-error="+filepath.Join(dir, "mysqld.log"), "--pid-file="+filepath.Join(dir, "mysqld.pid"))
+	if err := server.Start(); err != nil {
+		t.Fatalf("Failed to start mysqld: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = server.Process.Kill()
+		_ = server.Wait()
+	})
+	// Wait for the server, then create the test database.
+	db, err := sql.Open("mysql", fmt.Sprintf("root@unix(%s)/", socket))
+	if err != nil {
+		t.Fatalf("Failed to open mysqld: %v", err)
+	}
+	defer db.Close() //nolint:errcheck
+	deadline := time.Now().Add(30 * time.Second)
+	for db.Ping() != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("mysqld did not start, see %s", filepath.Join(dir, "mysqld.log"))
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	if _, err := db.Exec("CREATE DATABASE mysqlminger_test"); err != nil {
+		t.Fatalf("Failed to create the test database: %v", err)
+	}
+	return fmt.Sprintf("root@unix(%s)/mysqlminger_test", socket)
+}
+
+type MysqlmingerTestSuite struct {
+	suite.Suite
+	uri         string
+	config      *core.GomigerConfig
+	mysqlminger *Mysqlminger
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func (s *MysqlmingerTestSuite) SetupSuite() {
+	s.uri = startMysqld(s.T())
+}
+
+func (s *MysqlmingerTestSuite) SetupTest() {
+	s.config = &core.GomigerConfig{
+		URI:         s.uri,
+		SchemaStore: "schema_migrations",
+	}
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	s.mysqlminger = NewMysqlminger(s.config)
+	err := s.mysqlminger.Connect(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *MysqlmingerTestSuite) TearDownTest() {
+	if s.mysqlminger != nil && s.mysqlminger.DB != nil {
+		s.Require().NoError(s.mysqlminger.ReleaseLock(s.ctx, s.mysqlminger.lockOwner))
+		_, err := s.mysqlminger.DB.ExecContext(s.ctx, "DROP TABLE IF EXISTS users, schema_migrations, schema_migrations_lock")
+		s.Require().NoError(err)
+		s.Require().NoError(s.mysqlminger.DB.Close())
+	}
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// countUsers counts the rows of the users table, which is created by the test migrations.
+func (s *MysqlmingerTestSuite) countUsers() int {
+	var count int
+	err := s.mysqlminger.DB.QueryRowContext(s.ctx, "SELECT COUNT(*) FROM users").Scan(&count)
+	s.Require().NoError(err)
+	return count
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Connect() {
+	s.Require().NotNil(s.mysqlminger.DB)
+	// Connecting again keeps the schema table.
+	err := s.mysqlminger.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+	handle := s.mysqlminger.DB
+	err = s.mysqlminger.Connect(s.ctx)
+	s.Require().NoError(err)
+	_, err = s.mysqlminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	// The handle is kept, instead of leaking a new one.
+	s.Require().Same(handle, s.mysqlminger.DB)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Connect_InvalidURI() {
+	mysqlminger := NewMysqlminger(&core.GomigerConfig{URI: "invalid://uri", SchemaStore: "schema_migrations"})
+
+	err := mysqlminger.Connect(s.ctx)
+	s.Require().Error(err)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Connect_Unavailable() {
+	unavailableConfig := &core.GomigerConfig{
+		URI:         "root@tcp(localhost:1)/unavailable_db?timeout=2s", // Wrong port
+		SchemaStore: "schema_migrations",
+	}
+	mysqlminger := NewMysqlminger(unavailableConfig)
+
+	err := mysqlminger.Connect(s.ctx)
+	s.Require().Error(err)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_ApplyMigration_Success() {
+	migration := core.Migration{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			_, err := s.mysqlminger.DB.ExecContext(ctx, "CREATE TABLE users (name VARCHAR(255))")
+			return err
+		},
+		Checksum: "checksum-v1",
+	}
+	err := s.mysqlminger.ApplyMigration(s.ctx, migration)
+	s.Require().NoError(err)
+	// Verify the data and the schema.
+	s.Require().Zero(s.countUsers())
+	schema, err := s.mysqlminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+	s.Require().Equal("checksum-v1", schema.Checksum)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_ApplyMigration_PartialFailureMarksDirty() {
+	migration := core.Migration{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			if _, err := s.mysqlminger.DB.ExecContext(ctx, "CREATE TABLE users (name VARCHAR(255))"); err != nil {
+				return err
+			}
+			return fmt.Errorf("migration failed")
+		},
+	}
+	err := s.mysqlminger.ApplyMigration(s.ctx, migration)
+	s.Require().Error(err)
+	s.ErrorContains(err, "may be partially applied")
+	// The DDL is committed, so the migration is marked as dirty.
+	s.Require().Zero(s.countUsers())
+	schema, err := s.mysqlminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_RevertMigration_FailureMarksDirty() {
+	err := s.mysqlminger.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+	migration := core.Migration{
+		Version: "1.0.0",
+		Down:    func(ctx context.Context) error { return fmt.Errorf("revert failed") },
+	}
+
+	err = s.mysqlminger.RevertMigration(s.ctx, migration)
+	s.Require().Error(err)
+	s.ErrorContains(err, "failed to revert migration")
+	// Verify that the schema status is set to Dirty.
+	schema, err := s.mysqlminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Up_FreshDatabase() {
+	applied := []string{}
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		s.mysqlminger.Migrations = append(s.mysqlminger.Migrations, core.Migration{
+			Version: version,
+			Up: func(ctx context.Context) error {
+				applied = append(applied, version)
+				return nil
+			},
+		})
+	}
+	err := s.mysqlminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"1.0.0", "2.0.0"}, applied)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Status() {
+	s.mysqlminger.Migrations = []core.Migration{
+		{Version: "1.0.0", Up: func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}},
+		{Version: "2.0.0"},
+	}
+	err := s.mysqlminger.ApplyMigration(s.ctx, s.mysqlminger.Migrations[0])
+	s.Require().NoError(err)
+	err = s.mysqlminger.SaveSchema(s.ctx, core.Schema{Version: "0.1.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+
+	statuses, err := s.mysqlminger.Status(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(statuses, 3)
+	s.Require().Equal(core.StateApplied, statuses[0].State)
+	s.Require().NotNil(statuses[0].AppliedAt)
+	s.Require().GreaterOrEqual(statuses[0].Duration, 10*time.Millisecond)
+	s.Require().Equal(core.StatePending, statuses[1].State)
+	s.Require().Equal(core.StateOrphaned, statuses[2].State)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Validate() {
+	migration := core.Migration{
+		Version:  "1.0.0",
+		Up:       func(ctx context.Context) error { return nil },
+		Checksum: "checksum-v1",
+	}
+	err := s.mysqlminger.ApplyMigration(s.ctx, migration)
+	s.Require().NoError(err)
+	// Edit the migration code.
+	migration.Checksum = "checksum-v2"
+	s.mysqlminger.Migrations = []core.Migration{migration}
+	mismatches, err := s.mysqlminger.Validate(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal([]core.ChecksumMismatch{{Version: "1.0.0", Recorded: "checksum-v1", Current: "checksum-v2"}}, mismatches)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Retry() {
+	attempts := 0
+	s.mysqlminger.Migrations = []core.Migration{{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return fmt.Errorf("migration failed")
+			}
+			return nil
+		},
+	}}
+	err := s.mysqlminger.Up(s.ctx, "")
+	s.Require().Error(err)
+	// Dirty migrations are not applied again by Up.
+	err = s.mysqlminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal(1, attempts)
+
+	err = s.mysqlminger.Retry(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(2, attempts)
+	schema, err := s.mysqlminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
+
+func TestMysqlmingerTestSuite(t *testing.T) {
+	suite.Run(t, new(MysqlmingerTestSuite))
+}
+
+func TestMysqlminger_Conformance(t *testing.T) {
+	uri := startMysqld(t)
+	// The conformance plugins share a dedicated history table, dropped after each test.
+	plugintest.Run(t, func(t *testing.T) core.BaseMigratorAbstractMethods {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		mysqlminger := NewMysqlminger(&core.GomigerConfig{URI: uri, SchemaStore: "plugintest_migrations"})
+		if err := mysqlminger.Connect(ctx); err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		t.Cleanup(func() {
+			_, _ = mysqlminger.DB.ExecContext(context.Background(), "DROP TABLE IF EXISTS plugintest_migrations, plugintest_migrations_lock")
+			_ = mysqlminger.DB.Close()
+		})
+		return mysqlminger
+	})
+}
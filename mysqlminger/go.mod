@@ -0,0 +1,16 @@
+module github.com/ParteeLabs/gomiger/mysqlminger
+
+go 1.23.3
+
+require (
+	github.com/ParteeLabs/gomiger/core v0.0.0-20251015060613-e8484d17e217
+	github.com/go-sql-driver/mysql v1.8.1
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	filippo.io/edwards25519 v1.1.0 // indirect
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,175 @@
+package mysqlminger
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/go-sql-driver/mysql"
+)
+
+var _ core.Locker = (*Mysqlminger)(nil)
+
+// lockName is the name of the GET_LOCK lock. The names are global to the server,
+// so it is derived from the database & the history table.
+func (m *Mysqlminger) lockName() string {
+	name := "gomiger:" + m.dbName + "." + m.schemaStore
+	// The names are limited to 64 characters.
+	if len(name) > 64 {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(name))
+		name = fmt.Sprintf("gomiger:%x", h.Sum64())
+	}
+	return name
+}
+
+// isConnLost tells if an error is caused by a closed connection, so its session & lock are over.
+func isConnLost(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, mysql.ErrInvalidConn)
+}
+
+// discardConn closes the physical connection instead of returning it to the pool, so its session & lock are over.
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = conn.Close()
+}
+
+// releaseConn releases the lock of the connection, then returns it to the pool.
+// A pooled session keeps its lock, so the connection is discarded if the release fails.
+func (m *Mysqlminger) releaseConn(ctx context.Context, conn *sql.Conn) error {
+	if _, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", m.lockName()); err != nil {
+		discardConn(conn)
+		return err
+	}
+	return conn.Close()
+}
+
+// AcquireLock implements core.Locker.
+// The lock is a GET_LOCK lock, held by a dedicated connection for the whole run.
+// It is released when the connection is closed, so a crashed holder never blocks the next runs.
+func (m *Mysqlminger) AcquireLock(ctx context.Context, owner string, ttl time.Duration) error {
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+	if m.lockConn != nil {
+		if m.lockOwner == owner {
+			return nil
+		}
+		return core.ErrLockHeld
+	}
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock for owner: %s, Error: %w", owner, err)
+	}
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", m.lockName()).Scan(&acquired); err != nil {
+		// The lock may have been taken before the error.
+		discardConn(conn)
+		return fmt.Errorf("failed to acquire lock for owner: %s, Error: %w", owner, err)
+	}
+	if acquired.Int64 != 1 {
+		_ = conn.Close()
+		return core.ErrLockHeld
+	}
+	// Tell who holds the lock.
+	if _, err := conn.ExecContext(
+		ctx,
+		fmt.Sprintf("REPLACE INTO %s (id, owner, acquired_at) VALUES (1, ?, ?)", quoteIdent(m.schemaStore+"_lock")),
+		owner, time.Now(),
+	); err != nil {
+		_ = m.releaseConn(context.WithoutCancel(ctx), conn)
+		return fmt.Errorf("failed to acquire lock for owner: %s, Error: %w", owner, err)
+	}
+	m.lockConn, m.lockOwner = conn, owner
+	return nil
+}
+
+// RefreshLock implements core.Locker.
+// The GET_LOCK lock has no lease, the refresh only checks that its connection still holds it.
+func (m *Mysqlminger) RefreshLock(ctx context.Context, owner string, ttl time.Duration) error {
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+	if m.lockConn == nil || m.lockOwner != owner {
+		return core.ErrLockLost
+	}
+	var held sql.NullBool
+	err := m.lockConn.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?) = CONNECTION_ID()", m.lockName()).Scan(&held)
+	if isConnLost(err) || (err == nil && !held.Bool) {
+		_ = m.lockConn.Close()
+		m.lockConn, m.lockOwner = nil, ""
+		return core.ErrLockLost
+	}
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock for owner: %s, Error: %w", owner, err)
+	}
+	return nil
+}
+
+// ReleaseLock implements core.Locker.
+func (m *Mysqlminger) ReleaseLock(ctx context.Context, owner string) error {
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+	if m.lockConn == nil || m.lockOwner != owner {
+		return nil
+	}
+	conn := m.lockConn
+	m.lockConn, m.lockOwner = nil, ""
+	// The connection is discarded if the release fails, which ends its session & lock.
+	_, deleteErr := conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE owner = ?", quoteIdent(m.schemaStore+"_lock")), owner)
+	if err := errors.Join(deleteErr, m.releaseConn(ctx, conn)); err != nil && !isConnLost(err) {
+		return fmt.Errorf("failed to release lock for owner: %s, Error: %w", owner, err)
+	}
+	return nil
+}
+
+// holderID returns the connection id of the holder of the lock, 0 if it is free.
+func (m *Mysqlminger) holderID(ctx context.Context) (int64, error) {
+	var id sql.NullInt64
+	if err := m.DB.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?)", m.lockName()).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id.Int64, nil
+}
+
+// GetLock implements core.Locker.
+// The GET_LOCK lock never expires while its connection is alive, so the expiry is not a lease but now + core.DefaultLockTTL.
+func (m *Mysqlminger) GetLock(ctx context.Context) (*core.Lock, error) {
+	id, err := m.holderID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock: %w", err)
+	}
+	if id == 0 {
+		return nil, nil
+	}
+	lock := &core.Lock{ExpiresAt: time.Now().Add(core.DefaultLockTTL)}
+	if err := m.DB.QueryRowContext(
+		ctx,
+		fmt.Sprintf("SELECT owner, acquired_at FROM %s WHERE id = 1", quoteIdent(m.schemaStore+"_lock")),
+	).Scan(&lock.Owner, &lock.AcquiredAt); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get lock: %w", err)
+		}
+		lock.Owner = fmt.Sprintf("connection %d", id)
+	}
+	return lock, nil
+}
+
+// ForceReleaseLock implements core.Locker.
+// A GET_LOCK lock can only be released by its connection, so the connection holding it is killed.
+func (m *Mysqlminger) ForceReleaseLock(ctx context.Context) error {
+	id, err := m.holderID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to force release lock: %w", err)
+	}
+	if id == 0 {
+		return nil
+	}
+	if _, err := m.DB.ExecContext(ctx, fmt.Sprintf("KILL %d", id)); err != nil {
+		return fmt.Errorf("failed to force release lock: %w", err)
+	}
+	return nil
+}
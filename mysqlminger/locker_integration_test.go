@@ -0,0 +1,118 @@
+package mysqlminger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_AcquireLock_Success() {
+	err := s.mysqlminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Verify the lock of the connection.
+	lock, err := s.mysqlminger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(lock)
+	s.Require().Equal("owner-1", lock.Owner)
+	s.Require().False(lock.IsExpired())
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_AcquireLock_HeldByAnotherOwner() {
+	err := s.mysqlminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Try to acquire the held lock from another migrator.
+	other := NewMysqlminger(s.config)
+	s.Require().NoError(other.Connect(s.ctx))
+	defer other.DB.Close() //nolint:errcheck
+	err = other.AcquireLock(s.ctx, "owner-2", time.Minute)
+	s.Require().ErrorIs(err, core.ErrLockHeld)
+	// The same owner can re-acquire its lock.
+	err = s.mysqlminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_AcquireLock_FailedRecordReleasesTheLock() {
+	// The owner cannot be recorded without the lock table.
+	_, err := s.mysqlminger.DB.ExecContext(s.ctx, "DROP TABLE schema_migrations_lock")
+	s.Require().NoError(err)
+	err = s.mysqlminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().ErrorContains(err, "failed to acquire lock for owner: owner-1")
+	// The pooled session of the failed attempt does not keep the lock.
+	other := NewMysqlminger(s.config)
+	s.Require().NoError(other.Connect(s.ctx))
+	defer other.DB.Close() //nolint:errcheck
+	s.Require().NoError(other.AcquireLock(s.ctx, "owner-2", time.Minute))
+	s.Require().NoError(other.ReleaseLock(s.ctx, "owner-2"))
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_RefreshLock() {
+	err := s.mysqlminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	err = s.mysqlminger.RefreshLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Another owner cannot refresh the lock.
+	err = s.mysqlminger.RefreshLock(s.ctx, "owner-2", time.Minute)
+	s.Require().ErrorIs(err, core.ErrLockLost)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_ReleaseLock() {
+	err := s.mysqlminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Releasing the lock of another owner is a no-op.
+	err = s.mysqlminger.ReleaseLock(s.ctx, "owner-2")
+	s.Require().NoError(err)
+	lock, err := s.mysqlminger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(lock)
+	// Release the lock.
+	err = s.mysqlminger.ReleaseLock(s.ctx, "owner-1")
+	s.Require().NoError(err)
+	lock, err = s.mysqlminger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(lock)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_ForceUnlock() {
+	other := NewMysqlminger(s.config)
+	s.Require().NoError(other.Connect(s.ctx))
+	defer other.DB.Close() //nolint:errcheck
+	err := other.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Kill the connection of the holder.
+	err = s.mysqlminger.ForceUnlock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Eventually(func() bool {
+		lock, err := s.mysqlminger.GetLock(s.ctx)
+		return err == nil && lock == nil
+	}, 5*time.Second, 100*time.Millisecond)
+	err = s.mysqlminger.AcquireLock(s.ctx, "owner-2", time.Minute)
+	s.Require().NoError(err)
+	// The killed holder has lost its lock.
+	err = other.RefreshLock(s.ctx, "owner-1", time.Minute)
+	s.Require().ErrorIs(err, core.ErrLockLost)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Up_HoldsLock() {
+	s.mysqlminger.Migrations = []core.Migration{{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			// The lock is held for the whole run.
+			lock, err := s.mysqlminger.GetLock(ctx)
+			if err != nil {
+				return err
+			}
+			if lock == nil {
+				return fmt.Errorf("the lock is not held")
+			}
+			return nil
+		},
+	}}
+	err := s.mysqlminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	// The lock is released after the run.
+	lock, err := s.mysqlminger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(lock)
+}
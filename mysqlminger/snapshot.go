@@ -0,0 +1,76 @@
+package mysqlminger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// autoIncrement matches the counter of SHOW CREATE TABLE, which changes with the data.
+var autoIncrement = regexp.MustCompile(` AUTO_INCREMENT=\d+`)
+
+// Snapshot implements gomigertest.Snapshotter.
+// The state is the SHOW CREATE statement of the tables & views of the database, by type & name.
+// The schema & lock tables are excluded.
+func (m *Mysqlminger) Snapshot(ctx context.Context) (map[string]string, error) {
+	rows, err := m.DB.QueryContext(ctx, "SHOW FULL TABLES")
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+	tables := map[string]string{}
+	for rows.Next() {
+		var name, kind string
+		if err := rows.Scan(&name, &kind); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+		}
+		if name != m.schemaStore && name != m.schemaStore+"_lock" {
+			tables[name] = kind
+		}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+	snapshot := map[string]string{}
+	for name, kind := range tables {
+		prefix, show := "table ", "SHOW CREATE TABLE "
+		if kind == "VIEW" {
+			prefix, show = "view ", "SHOW CREATE VIEW "
+		}
+		// The statement is the second column, after the name.
+		columns, err := m.showCreate(ctx, show+quoteIdent(name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s: %s, Error: %w", strings.TrimSpace(prefix), name, err)
+		}
+		snapshot[prefix+name] = autoIncrement.ReplaceAllString(columns[1], "")
+	}
+	return snapshot, nil
+}
+
+// showCreate runs a SHOW CREATE statement, and returns the columns of its single row.
+func (m *Mysqlminger) showCreate(ctx context.Context, statement string) ([]string, error) {
+	rows, err := m.DB.QueryContext(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint:errcheck
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		return nil, errors.Join(rows.Err(), sql.ErrNoRows)
+	}
+	columns := make([]string, len(names))
+	dest := make([]any, len(names))
+	for i := range columns {
+		dest[i] = &columns[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
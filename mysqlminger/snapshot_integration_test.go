@@ -0,0 +1,74 @@
+package mysqlminger
+
+import (
+	"context"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/gomigertest"
+)
+
+var _ gomigertest.Snapshotter = (*Mysqlminger)(nil)
+
+// exec returns a migration function which runs a statement.
+func (s *MysqlmingerTestSuite) exec(statement string) core.MutationFunc {
+	return func(ctx context.Context) error {
+		_, err := s.mysqlminger.DB.ExecContext(ctx, statement)
+		return err
+	}
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Snapshot() {
+	_, err := s.mysqlminger.DB.ExecContext(s.ctx, "CREATE TABLE users (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(255))")
+	s.Require().NoError(err)
+	_, err = s.mysqlminger.DB.ExecContext(s.ctx, "INSERT INTO users (name) VALUES ('alice')")
+	s.Require().NoError(err)
+
+	snapshot, err := s.mysqlminger.Snapshot(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(snapshot, 1)
+	// The counter of the auto increment is ignored, as it changes with the data.
+	s.Require().Contains(snapshot["table users"], "CREATE TABLE `users`")
+	s.Require().NotContains(snapshot["table users"], "AUTO_INCREMENT=")
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Roundtrip() {
+	s.mysqlminger.Migrations = []core.Migration{
+		{
+			Version: "1.0.0",
+			Up:      s.exec("CREATE TABLE users (name VARCHAR(255))"),
+			Down:    s.exec("DROP TABLE users"),
+		},
+		{
+			Version: "2.0.0",
+			Up:      s.exec("CREATE VIEW user_names AS SELECT name FROM users"),
+			Down:    s.exec("DROP VIEW user_names"),
+		},
+	}
+	err := gomigertest.Verify(s.ctx, s.mysqlminger, s.mysqlminger)
+	s.Require().NoError(err)
+	_, err = s.mysqlminger.DB.ExecContext(s.ctx, "DROP VIEW user_names")
+	s.Require().NoError(err)
+}
+
+func (s *MysqlmingerTestSuite) TestMysqlminger_Roundtrip_Irreversible() {
+	s.mysqlminger.Migrations = []core.Migration{
+		{
+			Version: "1.0.0",
+			Up:      s.exec("CREATE TABLE users (name VARCHAR(255))"),
+			Down:    s.exec("DROP TABLE users"),
+		},
+		{
+			Version: "2.0.0",
+			Up:      s.exec("CREATE INDEX users_name ON users (name)"),
+			// The down forgets to drop the index.
+			Down: func(ctx context.Context) error { return nil },
+		},
+	}
+	err := gomigertest.Verify(s.ctx, s.mysqlminger, s.mysqlminger)
+	var irreversible *gomigertest.IrreversibleError
+	s.Require().ErrorAs(err, &irreversible)
+	s.Require().Equal("2.0.0", irreversible.Version)
+	s.Require().Equal(gomigertest.StageDown, irreversible.Stage)
+	s.Require().Len(irreversible.Diff, 1)
+	s.Require().Contains(irreversible.Diff[0], "~ table users:")
+}
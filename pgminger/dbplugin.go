@@ -0,0 +1,269 @@
+// Package pgminger provides PostgreSQL implementation of the Gomiger interface.
+// Extended from core.BaseMigrator.
+package pgminger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Pgminger implements core.DbPlugin for PostgreSQL.
+// Each migration runs with its schema row update in a single SQL transaction, whatever the Transactional config,
+// query the database with Conn in the migrations so the queries are part of it.
+type Pgminger struct {
+	*core.BaseMigrator
+	uri  string
+	Pool *pgxpool.Pool
+	// schemaName is the Postgres schema of the history table, empty to use the search_path.
+	schemaName string
+	// schemaTable is the quoted (and qualified) name of the history table.
+	schemaTable string
+
+	// lockMu guards the connection which holds the advisory lock.
+	lockMu    sync.Mutex
+	lockConn  *pgx.Conn
+	lockOwner string
+}
+
+// NewPgminger creates a new Pgminger plugin.
+// The schema store is the history table name, qualified by its Postgres schema if needed, e.g. "gomiger.schema_migrations".
+// An unqualified table is resolved by the search_path, which can be set in the URI, e.g. "postgres://host/db?search_path=app".
+func NewPgminger(cfg *core.GomigerConfig) *Pgminger {
+	pgminger := &Pgminger{
+		BaseMigrator: &core.BaseMigrator{
+			Migrations:        []core.Migration{},
+			OutOfOrder:        cfg.OutOfOrder,
+			AllowIrreversible: cfg.AllowIrreversible,
+			Observers:         cfg.Observers,
+		},
+		uri: cfg.URI,
+	}
+	identifier := pgx.Identifier{cfg.SchemaStore}
+	if schemaName, table, ok := strings.Cut(cfg.SchemaStore, "."); ok {
+		pgminger.schemaName = schemaName
+		identifier = pgx.Identifier{schemaName, table}
+	}
+	pgminger.schemaTable = identifier.Sanitize()
+	pgminger.BaseMigratorAbstractMethods = pgminger
+	pgminger.Locker = pgminger
+	return pgminger
+}
+
+// Connect implements core.DbPlugin.
+// It is a no-op when the plugin is connected, a failed connection is closed.
+func (p *Pgminger) Connect(ctx context.Context) (err error) {
+	if p.Pool != nil {
+		return nil
+	}
+	defer func() {
+		if err != nil && p.Pool != nil {
+			p.Pool.Close()
+			p.Pool = nil
+		}
+	}()
+	if p.Pool, err = pgxpool.New(ctx, p.uri); err != nil {
+		return fmt.Errorf("failed to parse the connection string: %w", err)
+	}
+	if err = p.Pool.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if p.schemaName != "" {
+		if _, err = p.Pool.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+pgx.Identifier{p.schemaName}.Sanitize()); err != nil {
+			return fmt.Errorf("failed to create schema: %s, Error: %w", p.schemaName, err)
+		}
+	}
+	if _, err = p.Pool.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version TEXT PRIMARY KEY,
+		timestamp TIMESTAMPTZ NOT NULL,
+		status TEXT NOT NULL,
+		duration BIGINT NOT NULL DEFAULT 0,
+		checksum TEXT NOT NULL DEFAULT '',
+		baselined BOOLEAN NOT NULL DEFAULT FALSE,
+		applied_by TEXT NOT NULL DEFAULT '',
+		gomiger_version TEXT NOT NULL DEFAULT '',
+		app_version TEXT NOT NULL DEFAULT '',
+		direction TEXT NOT NULL DEFAULT '',
+		error TEXT NOT NULL DEFAULT ''
+	)`, p.schemaTable)); err != nil {
+		return fmt.Errorf("failed to create schema table: %s, Error: %w", p.schemaTable, err)
+	}
+	return nil
+}
+
+// schemaColumns are the columns scanned by scanSchema.
+const schemaColumns = "version, timestamp, status, duration, checksum, baselined, applied_by, gomiger_version, app_version, direction, error"
+
+func scanSchema(row pgx.Row) (*core.Schema, error) {
+	var (
+		schema    core.Schema
+		status    string
+		duration  int64
+		direction string
+	)
+	if err := row.Scan(
+		&schema.Version, &schema.Timestamp, &status, &duration, &schema.Checksum, &schema.Baselined,
+		&schema.AppliedBy, &schema.GomigerVersion, &schema.AppVersion, &direction, &schema.Error,
+	); err != nil {
+		return nil, err
+	}
+	schema.Status = core.SchemaStatus(status)
+	schema.Duration = time.Duration(duration)
+	schema.Direction = core.Direction(direction)
+	return &schema, nil
+}
+
+// GetSchema implements core.DbPlugin.
+func (p *Pgminger) GetSchema(ctx context.Context, version string) (*core.Schema, error) {
+	schema, err := scanSchema(p.Pool.QueryRow(
+		ctx,
+		fmt.Sprintf("SELECT %s FROM %s WHERE version = $1", schemaColumns, p.schemaTable),
+		version,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get schema at version: %s, Error: %w", version, core.ErrSchemaNotFound)
+		}
+		return nil, fmt.Errorf("failed to get schema: %w", err)
+	}
+	return schema, nil
+}
+
+// ListSchemas implements core.DbPlugin.
+func (p *Pgminger) ListSchemas(ctx context.Context) ([]core.Schema, error) {
+	rows, err := p.Pool.Query(ctx, fmt.Sprintf("SELECT %s FROM %s ORDER BY version", schemaColumns, p.schemaTable))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	defer rows.Close()
+	schemas := []core.Schema{}
+	for rows.Next() {
+		schema, err := scanSchema(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list schemas: %w", err)
+		}
+		schemas = append(schemas, *schema)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	return schemas, nil
+}
+
+// insertSchema inserts the schema row of a version, it fails if the version already has one.
+func (p *Pgminger) insertSchema(ctx context.Context, schema core.Schema) error {
+	if _, err := p.Conn(ctx).Exec(
+		ctx,
+		fmt.Sprintf("INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)", p.schemaTable, schemaColumns),
+		schema.Version, schema.Timestamp, string(schema.Status), int64(schema.Duration), schema.Checksum, schema.Baselined,
+		schema.AppliedBy, schema.GomigerVersion, schema.AppVersion, string(schema.Direction), schema.Error,
+	); err != nil {
+		return fmt.Errorf("failed to insert schema at version: %s, Error: %w", schema.Version, err)
+	}
+	return nil
+}
+
+// SaveSchema implements core.DbPlugin.
+func (p *Pgminger) SaveSchema(ctx context.Context, schema core.Schema) error {
+	if _, err := p.Conn(ctx).Exec(
+		ctx,
+		fmt.Sprintf(`INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (version) DO UPDATE SET
+			timestamp = EXCLUDED.timestamp,
+			status = EXCLUDED.status,
+			duration = EXCLUDED.duration,
+			checksum = EXCLUDED.checksum,
+			baselined = EXCLUDED.baselined,
+			applied_by = EXCLUDED.applied_by,
+			gomiger_version = EXCLUDED.gomiger_version,
+			app_version = EXCLUDED.app_version,
+			direction = EXCLUDED.direction,
+			error = EXCLUDED.error`, p.schemaTable, schemaColumns),
+		schema.Version, schema.Timestamp, string(schema.Status), int64(schema.Duration), schema.Checksum, schema.Baselined,
+		schema.AppliedBy, schema.GomigerVersion, schema.AppVersion, string(schema.Direction), schema.Error,
+	); err != nil {
+		return fmt.Errorf("failed to save schema at version: %s, Error: %w", schema.Version, err)
+	}
+	return nil
+}
+
+// DeleteSchema implements core.DbPlugin.
+func (p *Pgminger) DeleteSchema(ctx context.Context, version string) error {
+	if _, err := p.Conn(ctx).Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE version = $1", p.schemaTable), version); err != nil {
+		return fmt.Errorf("failed to delete schema at version: %s, Error: %w", version, err)
+	}
+	return nil
+}
+
+// updateSchemaStatus records the status of a migration, stamped with its execution and the error of a failed one.
+// It is recorded even if the context is cancelled, e.g. by the timeout which failed the migration.
+func (p *Pgminger) updateSchemaStatus(ctx context.Context, mi core.Migration, status core.SchemaStatus, duration time.Duration, cause error) error {
+	schema := core.Schema{Status: status, Duration: duration}
+	core.ExecutionFromContext(ctx).Stamp(&schema, cause)
+	if _, err := p.Pool.Exec(
+		context.WithoutCancel(ctx),
+		fmt.Sprintf(
+			"UPDATE %s SET status = $1, duration = $2, applied_by = $3, gomiger_version = $4, app_version = $5, direction = $6, error = $7 WHERE version = $8",
+			p.schemaTable,
+		),
+		string(schema.Status), int64(schema.Duration), schema.AppliedBy, schema.GomigerVersion, schema.AppVersion, string(schema.Direction), schema.Error, mi.Version,
+	); err != nil {
+		return fmt.Errorf("failed to update schema status at version: %s to '%s', please recover it with the force or retry command, Error: %w", mi.Version, status, err)
+	}
+	return nil
+}
+
+// ApplyMigration implements core.DbPlugin.
+func (p *Pgminger) ApplyMigration(ctx context.Context, mi core.Migration) error {
+	if !mi.DisableTransaction {
+		return p.applyMigrationInTransaction(ctx, mi)
+	}
+	// Mark the migration as in progress (create a new schema).
+	startedAt := time.Now()
+	schema := core.Schema{
+		Version:   mi.Version,
+		Status:    core.InProgress,
+		Timestamp: startedAt,
+		Checksum:  mi.Checksum,
+	}
+	core.ExecutionFromContext(ctx).Stamp(&schema, nil)
+	if err := p.insertSchema(ctx, schema); err != nil {
+		return fmt.Errorf("failed to apply migration at version: %s, Error: %w", mi.Version, err)
+	}
+	// Run the migration.
+	if err := mi.Up(ctx); err != nil {
+		// Mark the migration as dirty.
+		if err := p.updateSchemaStatus(ctx, mi, core.Dirty, 0, err); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to apply migration %s: %w", mi.Version, err)
+	}
+	// Mark the migration as applied.
+	return p.updateSchemaStatus(ctx, mi, core.Applied, time.Since(startedAt), nil)
+}
+
+// RevertMigration implements core.DbPlugin.
+func (p *Pgminger) RevertMigration(ctx context.Context, mi core.Migration) error {
+	if !mi.DisableTransaction {
+		return p.revertMigrationInTransaction(ctx, mi)
+	}
+	if err := mi.Down(ctx); err != nil {
+		// Mark the migration as dirty.
+		if err := p.updateSchemaStatus(ctx, mi, core.Dirty, 0, err); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to revert migration %s: %w", mi.Version, err)
+	}
+	// Delete the schema.
+	if err := p.DeleteSchema(ctx, mi.Version); err != nil {
+		return fmt.Errorf("%w, please recover it with 'force %s --status pending'", err, mi.Version)
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package pgminger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/plugintest"
+	"github.com/stretchr/testify/suite"
+)
+
+// startPostgres starts a throwaway Postgres server with the initdb & pg_ctl binaries of the PATH, and returns its URI.
+// PGMINGER_TEST_URI runs the tests against an existing server instead.
+// The tests are skipped when none of them is available.
+func startPostgres(t *testing.T) string {
+	if uri := os.Getenv("PGMINGER_TEST_URI"); uri != "" {
+		return uri
+	}
+	initdb, err := exec.LookPath("initdb")
+	if err != nil {
+		t.Skip("initdb is not in the PATH, install Postgres or set PGMINGER_TEST_URI")
+	}
+	pgCtl, err := exec.LookPath("pg_ctl")
+	if err != nil {
+		t.Skip("pg_ctl is not in the PATH, install Postgres or set PGMINGER_TEST_URI")
+	}
+	// Pick a free port.
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data")
+	if out, err := exec.Command(initdb, "-D", data, "-U", "postgres", "-A", "trust", "--no-sync").CombinedOutput(); err != nil {
+		t.Fatalf("Failed to init the Postgres data folder: %v\n%s", err, out)
+	}
+	options := fmt.Sprintf("-p %d -k '' -c listen_addresses=localhost -c fsync=off", port)
+	if out, err := exec.Command(pgCtl, "-D", data, "-l", filepath.Join(dir, "postgres.log"), "-o", options, "-w", "start").CombinedOutput(); err != nil {
+		t.Fatalf("Failed to start Postgres: %v\n%s", err, out)
+	}
+	t.Cleanup(func() {
+		_ = exec.Command(pgCtl, "-D", data, "-m", "immediate", "-w", "stop").Run()
+	})
+	return fmt.Sprintf("postgres://postgres@localhost:%d/postgres?sslmode=disable", port)
+}
+
+type PgmingerTestSuite struct {
+	suite.Suite
+	uri      string
+	config   *core.GomigerConfig
+	pgminger *Pgminger
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func (s *PgmingerTestSuite) SetupSuite() {
+	s.uri = startPostgres(s.T())
+}
+
+func (s *PgmingerTestSuite) SetupTest() {
+	s.config = &core.GomigerConfig{
+		URI:         s.uri,
+		SchemaStore: "schema_migrations",
+	}
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	s.pgminger = NewPgminger(s.config)
+	err := s.pgminger.Connect(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *PgmingerTestSuite) TearDownTest() {
+	if s.pgminger != nil && s.pgminger.Pool != nil {
+		_, err := s.pgminger.Pool.Exec(s.ctx, "DROP TABLE IF EXISTS users, schema_migrations; DROP SCHEMA IF EXISTS gomiger CASCADE")
+		s.Require().NoError(err)
+		s.Require().NoError(s.pgminger.ReleaseLock(s.ctx, s.pgminger.lockOwner))
+		s.pgminger.Pool.Close()
+	}
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// countUsers counts the rows of the users table, which is created by the test migrations.
+func (s *PgmingerTestSuite) countUsers() int {
+	var count int
+	err := s.pgminger.Pool.QueryRow(s.ctx, "SELECT COUNT(*) FROM users").Scan(&count)
+	s.Require().NoError(err)
+	return count
+}
+
+func (s *PgmingerTestSuite) createUsers() {
+	_, err := s.pgminger.Pool.Exec(s.ctx, "CREATE TABLE users (name TEXT)")
+	s.Require().NoError(err)
+}
+
+func (s *PgmingerTestSuite) insertUser(ctx context.Context, name string) error {
+	_, err := s.pgminger.Conn(ctx).Exec(ctx, "INSERT INTO users (name) VALUES ($1)", name)
+	return err
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Connect() {
+	s.Require().NotNil(s.pgminger.Pool)
+	// Connecting again keeps the schema table.
+	err := s.pgminger.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+	handle := s.pgminger.Pool
+	err = s.pgminger.Connect(s.ctx)
+	s.Require().NoError(err)
+	_, err = s.pgminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	// The handle is kept, instead of leaking a new one.
+	s.Require().Same(handle, s.pgminger.Pool)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Connect_InvalidURI() {
+	pgminger := NewPgminger(&core.GomigerConfig{URI: "invalid://uri", SchemaStore: "schema_migrations"})
+
+	err := pgminger.Connect(s.ctx)
+	s.Require().Error(err)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Connect_Unavailable() {
+	unavailableConfig := &core.GomigerConfig{
+		URI:         "postgres://postgres@localhost:1/postgres?sslmode=disable&connect_timeout=2", // Wrong port
+		SchemaStore: "schema_migrations",
+	}
+	pgminger := NewPgminger(unavailableConfig)
+
+	err := pgminger.Connect(s.ctx)
+	s.Require().Error(err)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Connect_QualifiedSchemaStore() {
+	pgminger := NewPgminger(&core.GomigerConfig{URI: s.uri, SchemaStore: "gomiger.schema_migrations"})
+	err := pgminger.Connect(s.ctx)
+	s.Require().NoError(err)
+	defer pgminger.Pool.Close()
+
+	err = pgminger.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+	// The history table is created in the gomiger schema, not in the search_path.
+	var count int
+	err = pgminger.Pool.QueryRow(s.ctx, "SELECT COUNT(*) FROM gomiger.schema_migrations").Scan(&count)
+	s.Require().NoError(err)
+	s.Require().Equal(1, count)
+	schemas, err := s.pgminger.ListSchemas(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(schemas)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Connect_SearchPath() {
+	_, err := s.pgminger.Pool.Exec(s.ctx, "CREATE SCHEMA gomiger")
+	s.Require().NoError(err)
+	uri, err := url.Parse(s.uri)
+	s.Require().NoError(err)
+	query := uri.Query()
+	query.Set("search_path", "gomiger")
+	uri.RawQuery = query.Encode()
+	pgminger := NewPgminger(&core.GomigerConfig{URI: uri.String(), SchemaStore: "schema_migrations"})
+	err = pgminger.Connect(s.ctx)
+	s.Require().NoError(err)
+	defer pgminger.Pool.Close()
+
+	var count int
+	err = pgminger.Pool.QueryRow(s.ctx, "SELECT COUNT(*) FROM gomiger.schema_migrations").Scan(&count)
+	s.Require().NoError(err)
+	s.Require().Zero(count)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_ApplyMigration_Success() {
+	s.createUsers()
+	migration := core.Migration{
+		Version:  "1.0.0",
+		Up:       func(ctx context.Context) error { return s.insertUser(ctx, "alice") },
+		Checksum: "checksum-v1",
+	}
+	err := s.pgminger.ApplyMigration(s.ctx, migration)
+	s.Require().NoError(err)
+	// Verify the data and the schema.
+	s.Require().Equal(1, s.countUsers())
+	schema, err := s.pgminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+	s.Require().Equal("checksum-v1", schema.Checksum)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_ApplyMigration_DisableTransaction() {
+	migration := core.Migration{
+		Version:            "1.0.0",
+		Up:                 func(ctx context.Context) error { return fmt.Errorf("migration failed") },
+		DisableTransaction: true,
+	}
+	err := s.pgminger.ApplyMigration(s.ctx, migration)
+	s.Require().Error(err)
+	s.ErrorContains(err, "failed to apply migration")
+	// Without transaction, the failed migration is marked as dirty.
+	schema, err := s.pgminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_RevertMigration_DisableTransaction() {
+	err := s.pgminger.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+	migration := core.Migration{
+		Version:            "1.0.0",
+		Down:               func(ctx context.Context) error { return fmt.Errorf("revert failed") },
+		DisableTransaction: true,
+	}
+
+	err = s.pgminger.RevertMigration(s.ctx, migration)
+	s.Require().Error(err)
+	s.ErrorContains(err, "failed to revert migration")
+	// Verify that the schema status is set to Dirty.
+	schema, err := s.pgminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Up_FreshDatabase() {
+	applied := []string{}
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		s.pgminger.Migrations = append(s.pgminger.Migrations, core.Migration{
+			Version: version,
+			Up: func(ctx context.Context) error {
+				applied = append(applied, version)
+				return nil
+			},
+		})
+	}
+	err := s.pgminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"1.0.0", "2.0.0"}, applied)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Status() {
+	s.pgminger.Migrations = []core.Migration{
+		{Version: "1.0.0", Up: func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}},
+		{Version: "2.0.0"},
+	}
+	err := s.pgminger.ApplyMigration(s.ctx, s.pgminger.Migrations[0])
+	s.Require().NoError(err)
+	err = s.pgminger.SaveSchema(s.ctx, core.Schema{Version: "0.1.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+
+	statuses, err := s.pgminger.Status(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(statuses, 3)
+	s.Require().Equal(core.StateApplied, statuses[0].State)
+	s.Require().NotNil(statuses[0].AppliedAt)
+	s.Require().GreaterOrEqual(statuses[0].Duration, 10*time.Millisecond)
+	s.Require().Equal(core.StatePending, statuses[1].State)
+	s.Require().Equal(core.StateOrphaned, statuses[2].State)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Validate() {
+	migration := core.Migration{
+		Version:  "1.0.0",
+		Up:       func(ctx context.Context) error { return nil },
+		Checksum: "checksum-v1",
+	}
+	err := s.pgminger.ApplyMigration(s.ctx, migration)
+	s.Require().NoError(err)
+	// Edit the migration code.
+	migration.Checksum = "checksum-v2"
+	s.pgminger.Migrations = []core.Migration{migration}
+	mismatches, err := s.pgminger.Validate(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal([]core.ChecksumMismatch{{Version: "1.0.0", Recorded: "checksum-v1", Current: "checksum-v2"}}, mismatches)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Retry() {
+	attempts := 0
+	s.pgminger.Migrations = []core.Migration{{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return fmt.Errorf("migration failed")
+			}
+			return nil
+		},
+		DisableTransaction: true,
+	}}
+	err := s.pgminger.Up(s.ctx, "")
+	s.Require().Error(err)
+	// Dirty migrations are not applied again by Up.
+	err = s.pgminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal(1, attempts)
+
+	err = s.pgminger.Retry(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(2, attempts)
+	schema, err := s.pgminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
+
+func TestPgmingerTestSuite(t *testing.T) {
+	suite.Run(t, new(PgmingerTestSuite))
+}
+
+func TestPgminger_Conformance(t *testing.T) {
+	uri := startPostgres(t)
+	// The conformance plugins share a dedicated history table, dropped after each test.
+	plugintest.Run(t, func(t *testing.T) core.BaseMigratorAbstractMethods {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		pgminger := NewPgminger(&core.GomigerConfig{URI: uri, SchemaStore: "plugintest_migrations"})
+		if err := pgminger.Connect(ctx); err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		t.Cleanup(func() {
+			_, _ = pgminger.Pool.Exec(context.Background(), "DROP TABLE IF EXISTS plugintest_migrations")
+			pgminger.Pool.Close()
+		})
+		return pgminger
+	})
+}
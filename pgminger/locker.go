@@ -0,0 +1,148 @@
+package pgminger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ core.Locker = (*Pgminger)(nil)
+
+// lockKey is the key of the advisory lock, derived from the history table so two histories of a database do not share it.
+// It is positive, so it matches the classid & objid columns of pg_locks.
+func (p *Pgminger) lockKey() int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("gomiger:" + p.schemaTable))
+	return int64(h.Sum64() & 0x7fffffffffffffff)
+}
+
+// lockQuery selects the session holding the advisory lock of the key $1 in the current database.
+const lockQuery = `SELECT a.pid, a.application_name, a.backend_start
+	FROM pg_locks l JOIN pg_stat_activity a ON a.pid = l.pid
+	WHERE l.locktype = 'advisory' AND l.granted
+		AND l.database = (SELECT oid FROM pg_database WHERE datname = current_database())
+		AND l.classid::bigint = ($1::bigint >> 32) AND l.objid::bigint = ($1::bigint & 4294967295) AND l.objsubid = 1`
+
+// lockApplicationName is the application_name of the session holding the lock, it tells the owner to GetLock.
+// Postgres truncates the application_name to 63 bytes, so a longer owner is reported truncated.
+const lockApplicationName = "gomiger:"
+
+// AcquireLock implements core.Locker.
+// The lock is a session level advisory lock, held by a dedicated connection for the whole run.
+// It is released when the connection is closed, so a crashed holder never blocks the next runs.
+func (p *Pgminger) AcquireLock(ctx context.Context, owner string, ttl time.Duration) error {
+	p.lockMu.Lock()
+	defer p.lockMu.Unlock()
+	if p.lockConn != nil && !p.lockConn.IsClosed() {
+		if p.lockOwner == owner {
+			return nil
+		}
+		return core.ErrLockHeld
+	}
+	cfg, err := pgx.ParseConfig(p.uri)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock for owner: %s, Error: %w", owner, err)
+	}
+	cfg.RuntimeParams["application_name"] = lockApplicationName + owner
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock for owner: %s, Error: %w", owner, err)
+	}
+	var acquired bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", p.lockKey()).Scan(&acquired); err != nil {
+		_ = conn.Close(context.WithoutCancel(ctx))
+		return fmt.Errorf("failed to acquire lock for owner: %s, Error: %w", owner, err)
+	}
+	if !acquired {
+		_ = conn.Close(context.WithoutCancel(ctx))
+		return core.ErrLockHeld
+	}
+	p.lockConn, p.lockOwner = conn, owner
+	return nil
+}
+
+// RefreshLock implements core.Locker.
+// The advisory lock has no lease, the refresh only checks that its session is alive.
+func (p *Pgminger) RefreshLock(ctx context.Context, owner string, ttl time.Duration) error {
+	p.lockMu.Lock()
+	defer p.lockMu.Unlock()
+	if p.lockConn == nil || p.lockOwner != owner {
+		return core.ErrLockLost
+	}
+	if err := p.lockConn.Ping(ctx); err != nil {
+		if p.lockConn.IsClosed() {
+			// The session is over, so is the lock.
+			p.lockConn, p.lockOwner = nil, ""
+			return core.ErrLockLost
+		}
+		return fmt.Errorf("failed to refresh lock for owner: %s, Error: %w", owner, err)
+	}
+	return nil
+}
+
+// ReleaseLock implements core.Locker.
+func (p *Pgminger) ReleaseLock(ctx context.Context, owner string) error {
+	p.lockMu.Lock()
+	defer p.lockMu.Unlock()
+	if p.lockConn == nil || p.lockOwner != owner {
+		return nil
+	}
+	conn := p.lockConn
+	p.lockConn, p.lockOwner = nil, ""
+	if conn.IsClosed() {
+		// The session is over, so is the lock.
+		return nil
+	}
+	// Closing the session releases the lock, even if the unlock fails.
+	_, unlockErr := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", p.lockKey())
+	if err := errors.Join(unlockErr, conn.Close(ctx)); err != nil {
+		return fmt.Errorf("failed to release lock for owner: %s, Error: %w", owner, err)
+	}
+	return nil
+}
+
+// GetLock implements core.Locker.
+// The advisory lock never expires while its session is alive, so the expiry is not a lease but now + core.DefaultLockTTL.
+// The owner is read from the application_name of the session, truncated to 63 bytes by Postgres.
+func (p *Pgminger) GetLock(ctx context.Context) (*core.Lock, error) {
+	var (
+		pid             int32
+		applicationName string
+		backendStart    time.Time
+	)
+	if err := p.Pool.QueryRow(ctx, lockQuery, p.lockKey()).Scan(&pid, &applicationName, &backendStart); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get lock: %w", err)
+	}
+	owner, ok := strings.CutPrefix(applicationName, lockApplicationName)
+	if !ok || owner == "" {
+		owner = fmt.Sprintf("pid %d", pid)
+	}
+	return &core.Lock{
+		Owner:      owner,
+		AcquiredAt: backendStart,
+		ExpiresAt:  time.Now().Add(core.DefaultLockTTL),
+	}, nil
+}
+
+// ForceReleaseLock implements core.Locker.
+// An advisory lock can only be released by its session, so the session holding it is terminated.
+func (p *Pgminger) ForceReleaseLock(ctx context.Context) error {
+	rows, err := p.Pool.Query(ctx, "SELECT pg_terminate_backend(pid) FROM ("+lockQuery+") AS holder", p.lockKey())
+	if err != nil {
+		return fmt.Errorf("failed to force release lock: %w", err)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to force release lock: %w", err)
+	}
+	return nil
+}
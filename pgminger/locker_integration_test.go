@@ -0,0 +1,117 @@
+package pgminger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+func (s *PgmingerTestSuite) TestPgminger_AcquireLock_Success() {
+	err := s.pgminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Verify the lock of the session.
+	lock, err := s.pgminger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(lock)
+	s.Require().Equal("owner-1", lock.Owner)
+	s.Require().False(lock.IsExpired())
+}
+
+func (s *PgmingerTestSuite) TestPgminger_AcquireLock_HeldByAnotherOwner() {
+	err := s.pgminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Try to acquire the held lock from another migrator.
+	other := NewPgminger(s.config)
+	s.Require().NoError(other.Connect(s.ctx))
+	defer other.Pool.Close()
+	err = other.AcquireLock(s.ctx, "owner-2", time.Minute)
+	s.Require().ErrorIs(err, core.ErrLockHeld)
+	// The same owner can re-acquire its lock.
+	err = s.pgminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_AcquireLock_SessionClosed() {
+	err := s.pgminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// The lock is released with its session, e.g. when the holder crashes.
+	s.Require().NoError(s.pgminger.lockConn.Close(s.ctx))
+	other := NewPgminger(s.config)
+	s.Require().NoError(other.Connect(s.ctx))
+	defer other.Pool.Close()
+	err = other.AcquireLock(s.ctx, "owner-2", time.Minute)
+	s.Require().NoError(err)
+	defer other.ReleaseLock(s.ctx, "owner-2") //nolint:errcheck
+	// The crashed holder has lost its lock.
+	err = s.pgminger.RefreshLock(s.ctx, "owner-1", time.Minute)
+	s.Require().ErrorIs(err, core.ErrLockLost)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_RefreshLock() {
+	err := s.pgminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	err = s.pgminger.RefreshLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Another owner cannot refresh the lock.
+	err = s.pgminger.RefreshLock(s.ctx, "owner-2", time.Minute)
+	s.Require().ErrorIs(err, core.ErrLockLost)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_ReleaseLock() {
+	err := s.pgminger.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Releasing the lock of another owner is a no-op.
+	err = s.pgminger.ReleaseLock(s.ctx, "owner-2")
+	s.Require().NoError(err)
+	lock, err := s.pgminger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(lock)
+	// Release the lock.
+	err = s.pgminger.ReleaseLock(s.ctx, "owner-1")
+	s.Require().NoError(err)
+	lock, err = s.pgminger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(lock)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_ForceUnlock() {
+	other := NewPgminger(s.config)
+	s.Require().NoError(other.Connect(s.ctx))
+	defer other.Pool.Close()
+	err := other.AcquireLock(s.ctx, "owner-1", time.Minute)
+	s.Require().NoError(err)
+	// Terminate the session of the holder.
+	err = s.pgminger.ForceUnlock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Eventually(func() bool {
+		lock, err := s.pgminger.GetLock(s.ctx)
+		return err == nil && lock == nil
+	}, 5*time.Second, 100*time.Millisecond)
+	err = s.pgminger.AcquireLock(s.ctx, "owner-2", time.Minute)
+	s.Require().NoError(err)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Up_HoldsLock() {
+	s.pgminger.Migrations = []core.Migration{{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			// The lock is held for the whole run.
+			lock, err := s.pgminger.GetLock(ctx)
+			if err != nil {
+				return err
+			}
+			if lock == nil {
+				return fmt.Errorf("the lock is not held")
+			}
+			return nil
+		},
+	}}
+	err := s.pgminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	// The lock is released after the run.
+	lock, err := s.pgminger.GetLock(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Nil(lock)
+}
@@ -0,0 +1,69 @@
+package pgminger
+
+import (
+	"context"
+	"fmt"
+)
+
+// snapshotQuery lists the objects of the user schemas with their definition, the history table excluded:
+// the schemas, the relations (tables, views, sequences…) with their columns, the indexes and the constraints.
+const snapshotQuery = `
+SELECT 'schema ' || n.nspname, ''
+FROM pg_namespace n
+WHERE n.nspname !~ '^pg_' AND n.nspname <> 'information_schema'
+UNION ALL
+SELECT CASE c.relkind
+		WHEN 'r' THEN 'table ' WHEN 'p' THEN 'table ' WHEN 'v' THEN 'view ' WHEN 'm' THEN 'materialized view '
+		WHEN 'S' THEN 'sequence ' ELSE 'foreign table '
+	END || n.nspname || '.' || c.relname,
+	COALESCE(string_agg(
+		a.attname || ' ' || format_type(a.atttypid, a.atttypmod)
+			|| CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END
+			|| COALESCE(' DEFAULT ' || pg_get_expr(d.adbin, d.adrelid), ''),
+		', ' ORDER BY a.attnum
+	), '') || CASE WHEN c.relkind IN ('v', 'm') THEN ' AS ' || pg_get_viewdef(c.oid) ELSE '' END
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped AND c.relkind <> 'S'
+LEFT JOIN pg_attrdef d ON d.adrelid = c.oid AND d.adnum = a.attnum
+WHERE c.relkind IN ('r', 'p', 'v', 'm', 'S', 'f')
+	AND n.nspname !~ '^pg_' AND n.nspname <> 'information_schema'
+	AND c.oid IS DISTINCT FROM to_regclass($1)
+GROUP BY n.nspname, c.relname, c.relkind, c.oid
+UNION ALL
+SELECT 'index ' || n.nspname || '.' || c.relname, pg_get_indexdef(i.indexrelid)
+FROM pg_index i
+JOIN pg_class c ON c.oid = i.indexrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname !~ '^pg_' AND n.nspname <> 'information_schema'
+	AND i.indrelid IS DISTINCT FROM to_regclass($1)
+UNION ALL
+SELECT 'constraint ' || n.nspname || '.' || c.relname || '.' || con.conname, pg_get_constraintdef(con.oid)
+FROM pg_constraint con
+JOIN pg_class c ON c.oid = con.conrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname !~ '^pg_' AND n.nspname <> 'information_schema'
+	AND con.conrelid IS DISTINCT FROM to_regclass($1)`
+
+// Snapshot implements gomigertest.Snapshotter.
+// The state is the definition of the schemas, tables, views, sequences, indexes & constraints of the database, by type & name.
+// The history table and the system schemas are excluded.
+func (p *Pgminger) Snapshot(ctx context.Context) (map[string]string, error) {
+	rows, err := p.Pool.Query(ctx, snapshotQuery, p.schemaTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+	defer rows.Close()
+	snapshot := map[string]string{}
+	for rows.Next() {
+		var name, definition string
+		if err := rows.Scan(&name, &definition); err != nil {
+			return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+		}
+		snapshot[name] = definition
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+	return snapshot, nil
+}
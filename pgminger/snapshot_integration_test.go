@@ -0,0 +1,73 @@
+package pgminger
+
+import (
+	"context"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/gomigertest"
+)
+
+var _ gomigertest.Snapshotter = (*Pgminger)(nil)
+
+// exec returns a migration function which runs a statement in the transaction of the migration.
+func (s *PgmingerTestSuite) exec(statement string) core.MutationFunc {
+	return func(ctx context.Context) error {
+		_, err := s.pgminger.Conn(ctx).Exec(ctx, statement)
+		return err
+	}
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Snapshot() {
+	_, err := s.pgminger.Pool.Exec(s.ctx, "CREATE TABLE users (id INT PRIMARY KEY, name TEXT NOT NULL DEFAULT '')")
+	s.Require().NoError(err)
+	_, err = s.pgminger.Pool.Exec(s.ctx, "CREATE UNIQUE INDEX users_name ON users (name)")
+	s.Require().NoError(err)
+
+	snapshot, err := s.pgminger.Snapshot(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal("id integer NOT NULL, name text NOT NULL DEFAULT ''::text", snapshot["table public.users"])
+	s.Require().Equal("CREATE UNIQUE INDEX users_name ON public.users USING btree (name)", snapshot["index public.users_name"])
+	s.Require().Equal("PRIMARY KEY (id)", snapshot["constraint public.users.users_pkey"])
+	// The history table is excluded.
+	s.Require().NotContains(snapshot, "table public.schema_migrations")
+	s.Require().NotContains(snapshot, "index public.schema_migrations_pkey")
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Roundtrip() {
+	s.pgminger.Migrations = []core.Migration{
+		{
+			Version: "1.0.0",
+			Up:      s.exec("CREATE TABLE users (name TEXT)"),
+			Down:    s.exec("DROP TABLE users"),
+		},
+		{
+			Version: "2.0.0",
+			Up:      s.exec("ALTER TABLE users ADD COLUMN email TEXT UNIQUE"),
+			Down:    s.exec("ALTER TABLE users DROP COLUMN email"),
+		},
+	}
+	err := gomigertest.Verify(s.ctx, s.pgminger, s.pgminger)
+	s.Require().NoError(err)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_Roundtrip_Irreversible() {
+	s.pgminger.Migrations = []core.Migration{
+		{
+			Version: "1.0.0",
+			Up:      s.exec("CREATE TABLE users (name TEXT)"),
+			Down:    s.exec("DROP TABLE users"),
+		},
+		{
+			Version: "2.0.0",
+			Up:      s.exec("CREATE INDEX users_name ON users (name)"),
+			// The down forgets to drop the index.
+			Down: func(ctx context.Context) error { return nil },
+		},
+	}
+	err := gomigertest.Verify(s.ctx, s.pgminger, s.pgminger)
+	var irreversible *gomigertest.IrreversibleError
+	s.Require().ErrorAs(err, &irreversible)
+	s.Require().Equal("2.0.0", irreversible.Version)
+	s.Require().Equal(gomigertest.StageDown, irreversible.Stage)
+	s.Require().Equal([]string{"+ index public.users_name: CREATE INDEX users_name ON public.users USING btree (name)"}, irreversible.Diff)
+}
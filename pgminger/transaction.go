@@ -0,0 +1,92 @@
+package pgminger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DBTX is the query interface shared by *pgxpool.Pool and pgx.Tx.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+var (
+	_ DBTX = (*pgxpool.Pool)(nil)
+	_ DBTX = (pgx.Tx)(nil)
+)
+
+// txKey is the context key of the transaction of a running migration.
+type txKey struct{}
+
+// Conn returns the transaction of the running migration, or the pool when it runs without transaction.
+func (p *Pgminger) Conn(ctx context.Context) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return p.Pool
+}
+
+// inTransaction runs fn in a transaction, which is committed if fn succeeds and rolled back otherwise.
+// The context passed to fn carries the transaction, see Conn.
+func (p *Pgminger) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(context.WithoutCancel(ctx)); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to rollback transaction: %w", rbErr))
+		}
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// applyMigrationInTransaction runs the migration and inserts its schema row in a single transaction.
+// A failed migration is rolled back and stays pending, it is never marked as dirty.
+func (p *Pgminger) applyMigrationInTransaction(ctx context.Context, mi core.Migration) error {
+	if err := p.inTransaction(ctx, func(ctx context.Context) error {
+		startedAt := time.Now()
+		if err := mi.Up(ctx); err != nil {
+			return err
+		}
+		schema := core.Schema{
+			Version:   mi.Version,
+			Status:    core.Applied,
+			Timestamp: startedAt,
+			Duration:  time.Since(startedAt),
+			Checksum:  mi.Checksum,
+		}
+		core.ExecutionFromContext(ctx).Stamp(&schema, nil)
+		return p.insertSchema(ctx, schema)
+	}); err != nil {
+		return fmt.Errorf("failed to apply migration %s, the transaction is rolled back: %w", mi.Version, err)
+	}
+	return nil
+}
+
+// revertMigrationInTransaction reverts the migration and deletes its schema row in a single transaction.
+// A failed revert is rolled back and the migration stays applied.
+func (p *Pgminger) revertMigrationInTransaction(ctx context.Context, mi core.Migration) error {
+	if err := p.inTransaction(ctx, func(ctx context.Context) error {
+		if err := mi.Down(ctx); err != nil {
+			return err
+		}
+		return p.DeleteSchema(ctx, mi.Version)
+	}); err != nil {
+		return fmt.Errorf("failed to revert migration %s, the transaction is rolled back: %w", mi.Version, err)
+	}
+	return nil
+}
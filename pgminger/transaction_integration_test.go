@@ -0,0 +1,73 @@
+package pgminger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+func (s *PgmingerTestSuite) TestPgminger_ApplyMigration_TransactionRollback() {
+	migration := core.Migration{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			// DDL is transactional in Postgres.
+			if _, err := s.pgminger.Conn(ctx).Exec(ctx, "CREATE TABLE users (name TEXT)"); err != nil {
+				return err
+			}
+			return fmt.Errorf("migration failed")
+		},
+	}
+	err := s.pgminger.ApplyMigration(s.ctx, migration)
+	s.Require().ErrorContains(err, "the transaction is rolled back")
+	// Verify the table is rolled back and the migration is still pending.
+	var exists bool
+	err = s.pgminger.Pool.QueryRow(s.ctx, "SELECT to_regclass('users') IS NOT NULL").Scan(&exists)
+	s.Require().NoError(err)
+	s.Require().False(exists)
+	_, err = s.pgminger.GetSchema(s.ctx, migration.Version)
+	s.Require().ErrorIs(err, core.ErrSchemaNotFound)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_ApplyMigration_CreateIndexConcurrently() {
+	s.createUsers()
+	migration := core.Migration{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			_, err := s.pgminger.Conn(ctx).Exec(ctx, "CREATE INDEX CONCURRENTLY users_name_idx ON users (name)")
+			return err
+		},
+	}
+	// CREATE INDEX CONCURRENTLY cannot run in a transaction.
+	err := s.pgminger.ApplyMigration(s.ctx, migration)
+	s.Require().ErrorContains(err, "the transaction is rolled back")
+	// It runs once the migration opts out of the transaction.
+	migration.DisableTransaction = true
+	err = s.pgminger.ApplyMigration(s.ctx, migration)
+	s.Require().NoError(err)
+	schema, err := s.pgminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
+
+func (s *PgmingerTestSuite) TestPgminger_RevertMigration_TransactionRollback() {
+	s.createUsers()
+	err := s.pgminger.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied})
+	s.Require().NoError(err)
+	migration := core.Migration{
+		Version: "1.0.0",
+		Down: func(ctx context.Context) error {
+			if err := s.insertUser(ctx, "bob"); err != nil {
+				return err
+			}
+			return fmt.Errorf("revert failed")
+		},
+	}
+	err = s.pgminger.RevertMigration(s.ctx, migration)
+	s.Require().ErrorContains(err, "the transaction is rolled back")
+	// Verify the migration is still applied.
+	s.Require().Zero(s.countUsers())
+	schema, err := s.pgminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
@@ -0,0 +1,239 @@
+// Package sqliteminger provides SQLite implementation of the Gomiger interface.
+// Extended from core.BaseMigrator.
+package sqliteminger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	// Register the pure Go "sqlite" driver of database/sql.
+	_ "modernc.org/sqlite"
+)
+
+// Sqliteminger implements core.DbPlugin for SQLite.
+// Each migration runs with its schema row update in a single SQL transaction, whatever the Transactional config,
+// query the database with Conn in the migrations so the queries are part of it.
+type Sqliteminger struct {
+	*core.BaseMigrator
+	uri         string
+	DB          *sql.DB
+	schemaStore string
+}
+
+// NewSqliteminger creates a new Sqliteminger plugin.
+// The URI is the data source name of the driver, e.g. "file:app.db?_pragma=busy_timeout(5000)".
+func NewSqliteminger(cfg *core.GomigerConfig) *Sqliteminger {
+	sqliteminger := &Sqliteminger{
+		BaseMigrator: &core.BaseMigrator{
+			Migrations:        []core.Migration{},
+			OutOfOrder:        cfg.OutOfOrder,
+			AllowIrreversible: cfg.AllowIrreversible,
+			Observers:         cfg.Observers,
+		},
+		uri:         cfg.URI,
+		schemaStore: cfg.SchemaStore,
+	}
+	sqliteminger.BaseMigratorAbstractMethods = sqliteminger
+	return sqliteminger
+}
+
+// quoteIdent quotes an identifier, e.g. the schema table name.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Connect implements core.DbPlugin.
+// It is a no-op when the plugin is connected, a failed connection is closed.
+func (s *Sqliteminger) Connect(ctx context.Context) (err error) {
+	if s.DB != nil {
+		return nil
+	}
+	defer func() {
+		if err != nil && s.DB != nil {
+			_ = s.DB.Close()
+			s.DB = nil
+		}
+	}()
+	if s.DB, err = sql.Open("sqlite", s.uri); err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	if err = s.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	if _, err = s.DB.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version TEXT PRIMARY KEY,
+		timestamp DATETIME NOT NULL,
+		status TEXT NOT NULL,
+		duration INTEGER NOT NULL DEFAULT 0,
+		checksum TEXT NOT NULL DEFAULT '',
+		baselined BOOLEAN NOT NULL DEFAULT 0,
+		applied_by TEXT NOT NULL DEFAULT '',
+		gomiger_version TEXT NOT NULL DEFAULT '',
+		app_version TEXT NOT NULL DEFAULT '',
+		direction TEXT NOT NULL DEFAULT '',
+		error TEXT NOT NULL DEFAULT ''
+	)`, quoteIdent(s.schemaStore))); err != nil {
+		return fmt.Errorf("failed to create schema table: %s, Error: %w", s.schemaStore, err)
+	}
+	return nil
+}
+
+// scanner is a *sql.Row or *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// schemaColumns are the columns scanned by scanSchema.
+const schemaColumns = "version, timestamp, status, duration, checksum, baselined, applied_by, gomiger_version, app_version, direction, error"
+
+func scanSchema(row scanner) (*core.Schema, error) {
+	schema := &core.Schema{}
+	if err := row.Scan(&schema.Version, &schema.Timestamp, &schema.Status, &schema.Duration, &schema.Checksum, &schema.Baselined,
+		&schema.AppliedBy, &schema.GomigerVersion, &schema.AppVersion, &schema.Direction, &schema.Error,
+	); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// GetSchema implements core.DbPlugin.
+func (s *Sqliteminger) GetSchema(ctx context.Context, version string) (*core.Schema, error) {
+	schema, err := scanSchema(s.DB.QueryRowContext(
+		ctx,
+		fmt.Sprintf("SELECT %s FROM %s WHERE version = ?", schemaColumns, quoteIdent(s.schemaStore)),
+		version,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get schema at version: %s, Error: %w", version, core.ErrSchemaNotFound)
+		}
+		return nil, fmt.Errorf("failed to get schema: %w", err)
+	}
+	return schema, nil
+}
+
+// ListSchemas implements core.DbPlugin.
+func (s *Sqliteminger) ListSchemas(ctx context.Context) ([]core.Schema, error) {
+	rows, err := s.DB.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s ORDER BY version", schemaColumns, quoteIdent(s.schemaStore)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+	schemas := []core.Schema{}
+	for rows.Next() {
+		schema, err := scanSchema(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list schemas: %w", err)
+		}
+		schemas = append(schemas, *schema)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	return schemas, nil
+}
+
+// insertSchema inserts the schema row of a version, it fails if the version already has one.
+func (s *Sqliteminger) insertSchema(ctx context.Context, schema core.Schema) error {
+	if _, err := s.Conn(ctx).ExecContext(
+		ctx,
+		fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", quoteIdent(s.schemaStore), schemaColumns),
+		schema.Version, schema.Timestamp, schema.Status, schema.Duration, schema.Checksum, schema.Baselined,
+		schema.AppliedBy, schema.GomigerVersion, schema.AppVersion, schema.Direction, schema.Error,
+	); err != nil {
+		return fmt.Errorf("failed to insert schema at version: %s, Error: %w", schema.Version, err)
+	}
+	return nil
+}
+
+// SaveSchema implements core.DbPlugin.
+func (s *Sqliteminger) SaveSchema(ctx context.Context, schema core.Schema) error {
+	if _, err := s.Conn(ctx).ExecContext(
+		ctx,
+		fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", quoteIdent(s.schemaStore), schemaColumns),
+		schema.Version, schema.Timestamp, schema.Status, schema.Duration, schema.Checksum, schema.Baselined,
+		schema.AppliedBy, schema.GomigerVersion, schema.AppVersion, schema.Direction, schema.Error,
+	); err != nil {
+		return fmt.Errorf("failed to save schema at version: %s, Error: %w", schema.Version, err)
+	}
+	return nil
+}
+
+// DeleteSchema implements core.DbPlugin.
+func (s *Sqliteminger) DeleteSchema(ctx context.Context, version string) error {
+	if _, err := s.Conn(ctx).ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE version = ?", quoteIdent(s.schemaStore)), version); err != nil {
+		return fmt.Errorf("failed to delete schema at version: %s, Error: %w", version, err)
+	}
+	return nil
+}
+
+// updateSchemaStatus records the status of a migration, stamped with its execution and the error of a failed one.
+// It is recorded even if the context is cancelled, e.g. by the timeout which failed the migration.
+func (s *Sqliteminger) updateSchemaStatus(ctx context.Context, mi core.Migration, status core.SchemaStatus, duration time.Duration, cause error) error {
+	schema := core.Schema{Status: status, Duration: duration}
+	core.ExecutionFromContext(ctx).Stamp(&schema, cause)
+	if _, err := s.DB.ExecContext(
+		context.WithoutCancel(ctx),
+		fmt.Sprintf(
+			"UPDATE %s SET status = ?, duration = ?, applied_by = ?, gomiger_version = ?, app_version = ?, direction = ?, error = ? WHERE version = ?",
+			quoteIdent(s.schemaStore),
+		),
+		schema.Status, schema.Duration, schema.AppliedBy, schema.GomigerVersion, schema.AppVersion, schema.Direction, schema.Error, mi.Version,
+	); err != nil {
+		return fmt.Errorf("failed to update schema status at version: %s to '%s', please recover it with the force or retry command, Error: %w", mi.Version, status, err)
+	}
+	return nil
+}
+
+// ApplyMigration implements core.DbPlugin.
+func (s *Sqliteminger) ApplyMigration(ctx context.Context, mi core.Migration) error {
+	if !mi.DisableTransaction {
+		return s.applyMigrationInTransaction(ctx, mi)
+	}
+	// Mark the migration as in progress (create a new schema).
+	startedAt := time.Now()
+	schema := core.Schema{
+		Version:   mi.Version,
+		Status:    core.InProgress,
+		Timestamp: startedAt,
+		Checksum:  mi.Checksum,
+	}
+	core.ExecutionFromContext(ctx).Stamp(&schema, nil)
+	if err := s.insertSchema(ctx, schema); err != nil {
+		return fmt.Errorf("failed to apply migration at version: %s, Error: %w", mi.Version, err)
+	}
+	// Run the migration.
+	if err := mi.Up(ctx); err != nil {
+		// Mark the migration as dirty.
+		if err := s.updateSchemaStatus(ctx, mi, core.Dirty, 0, err); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to apply migration %s: %w", mi.Version, err)
+	}
+	// Mark the migration as applied.
+	return s.updateSchemaStatus(ctx, mi, core.Applied, time.Since(startedAt), nil)
+}
+
+// RevertMigration implements core.DbPlugin.
+func (s *Sqliteminger) RevertMigration(ctx context.Context, mi core.Migration) error {
+	if !mi.DisableTransaction {
+		return s.revertMigrationInTransaction(ctx, mi)
+	}
+	if err := mi.Down(ctx); err != nil {
+		// Mark the migration as dirty.
+		if err := s.updateSchemaStatus(ctx, mi, core.Dirty, 0, err); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to revert migration %s: %w", mi.Version, err)
+	}
+	// Delete the schema.
+	if err := s.DeleteSchema(ctx, mi.Version); err != nil {
+		return fmt.Errorf("%w, please recover it with 'force %s --status pending'", err, mi.Version)
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package sqliteminger
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/plugintest"
+	"github.com/stretchr/testify/suite"
+)
+
+type SqlitemingerTestSuite struct {
+	suite.Suite
+	config       *core.GomigerConfig
+	sqliteminger *Sqliteminger
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+func (s *SqlitemingerTestSuite) SetupTest() {
+	s.config = &core.GomigerConfig{
+		URI:         "file:" + filepath.Join(s.T().TempDir(), "sqliteminger_test.db"),
+		SchemaStore: "schema_migrations",
+	}
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	s.sqliteminger = NewSqliteminger(s.config)
+	err := s.sqliteminger.Connect(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *SqlitemingerTestSuite) TearDownTest() {
+	if s.sqliteminger != nil && s.sqliteminger.DB != nil {
+		err := s.sqliteminger.DB.Close()
+		s.Require().NoError(err)
+	}
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// countUsers counts the rows of the users table, which is created by the test migrations.
+func (s *SqlitemingerTestSuite) countUsers() int {
+	var count int
+	err := s.sqliteminger.DB.QueryRowContext(s.ctx, "SELECT COUNT(*) FROM users").Scan(&count)
+	s.Require().NoError(err)
+	return count
+}
+
+func (s *SqlitemingerTestSuite) createUsers() {
+	_, err := s.sqliteminger.DB.ExecContext(s.ctx, "CREATE TABLE users (name TEXT)")
+	s.Require().NoError(err)
+}
+
+func (s *SqlitemingerTestSuite) insertUser(ctx context.Context, name string) error {
+	_, err := s.sqliteminger.Conn(ctx).ExecContext(ctx, "INSERT INTO users (name) VALUES (?)", name)
+	return err
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_Connect() {
+	s.Require().NotNil(s.sqliteminger.DB)
+	// Connecting again keeps the schema table.
+	err := s.sqliteminger.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+	handle := s.sqliteminger.DB
+	err = s.sqliteminger.Connect(s.ctx)
+	s.Require().NoError(err)
+	_, err = s.sqliteminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	// The handle is kept, instead of leaking a new one.
+	s.Require().Same(handle, s.sqliteminger.DB)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_Connect_Unavailable() {
+	unavailableConfig := &core.GomigerConfig{
+		URI:         "file:" + filepath.Join(s.T().TempDir(), "missing", "unavailable.db"), // Missing folder
+		SchemaStore: "schema_migrations",
+	}
+	sqliteminger := NewSqliteminger(unavailableConfig)
+
+	err := sqliteminger.Connect(s.ctx)
+	s.Require().Error(err)
+	// The failed connection is closed, so the next Connect starts over.
+	s.Require().Nil(sqliteminger.DB)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_ApplyMigration_Success() {
+	s.createUsers()
+	migration := core.Migration{
+		Version:  "1.0.0",
+		Up:       func(ctx context.Context) error { return s.insertUser(ctx, "alice") },
+		Checksum: "checksum-v1",
+	}
+	err := s.sqliteminger.ApplyMigration(s.ctx, migration)
+	s.Require().NoError(err)
+	// Verify the data and the schema.
+	s.Require().Equal(1, s.countUsers())
+	schema, err := s.sqliteminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+	s.Require().Equal("checksum-v1", schema.Checksum)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_ApplyMigration_TransactionRollback() {
+	s.createUsers()
+	migration := core.Migration{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			if err := s.insertUser(ctx, "alice"); err != nil {
+				return err
+			}
+			return fmt.Errorf("migration failed")
+		},
+	}
+	err := s.sqliteminger.ApplyMigration(s.ctx, migration)
+	s.Require().ErrorContains(err, "the transaction is rolled back")
+	// Verify the data is rolled back and the migration is still pending.
+	s.Require().Zero(s.countUsers())
+	_, err = s.sqliteminger.GetSchema(s.ctx, migration.Version)
+	s.Require().ErrorIs(err, core.ErrSchemaNotFound)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_ApplyMigration_DisableTransaction() {
+	migration := core.Migration{
+		Version:            "1.0.0",
+		Up:                 func(ctx context.Context) error { return fmt.Errorf("migration failed") },
+		DisableTransaction: true,
+	}
+	err := s.sqliteminger.ApplyMigration(s.ctx, migration)
+	s.Require().Error(err)
+	s.ErrorContains(err, "failed to apply migration")
+	// Without transaction, the failed migration is marked as dirty.
+	schema, err := s.sqliteminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_ApplyMigration_DisableTransactionSuccess() {
+	migration := core.Migration{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			// VACUUM cannot run in a transaction.
+			_, err := s.sqliteminger.Conn(ctx).ExecContext(ctx, "VACUUM")
+			return err
+		},
+		DisableTransaction: true,
+	}
+	err := s.sqliteminger.ApplyMigration(s.ctx, migration)
+	s.Require().NoError(err)
+	schema, err := s.sqliteminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_RevertMigration_TransactionRollback() {
+	s.createUsers()
+	err := s.sqliteminger.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+	migration := core.Migration{
+		Version: "1.0.0",
+		Down: func(ctx context.Context) error {
+			if err := s.insertUser(ctx, "bob"); err != nil {
+				return err
+			}
+			return fmt.Errorf("revert failed")
+		},
+	}
+	err = s.sqliteminger.RevertMigration(s.ctx, migration)
+	s.Require().ErrorContains(err, "the transaction is rolled back")
+	// Verify the migration is still applied.
+	s.Require().Zero(s.countUsers())
+	schema, err := s.sqliteminger.GetSchema(s.ctx, migration.Version)
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_RevertMigration_DisableTransaction() {
+	err := s.sqliteminger.SaveSchema(s.ctx, core.Schema{Version: "1.0.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+	migration := core.Migration{
+		Version:            "1.0.0",
+		Down:               func(ctx context.Context) error { return fmt.Errorf("revert failed") },
+		DisableTransaction: true,
+	}
+
+	err = s.sqliteminger.RevertMigration(s.ctx, migration)
+	s.Require().Error(err)
+	s.ErrorContains(err, "failed to revert migration")
+	// Verify that the schema status is set to Dirty.
+	schema, err := s.sqliteminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Dirty, schema.Status)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_Up_FreshDatabase() {
+	applied := []string{}
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		s.sqliteminger.Migrations = append(s.sqliteminger.Migrations, core.Migration{
+			Version: version,
+			Up: func(ctx context.Context) error {
+				applied = append(applied, version)
+				return nil
+			},
+		})
+	}
+	err := s.sqliteminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"1.0.0", "2.0.0"}, applied)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_Status() {
+	s.sqliteminger.Migrations = []core.Migration{
+		{Version: "1.0.0", Up: func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}},
+		{Version: "2.0.0"},
+	}
+	err := s.sqliteminger.ApplyMigration(s.ctx, s.sqliteminger.Migrations[0])
+	s.Require().NoError(err)
+	err = s.sqliteminger.SaveSchema(s.ctx, core.Schema{Version: "0.1.0", Status: core.Applied, Timestamp: time.Now()})
+	s.Require().NoError(err)
+
+	statuses, err := s.sqliteminger.Status(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(statuses, 3)
+	s.Require().Equal(core.StateApplied, statuses[0].State)
+	s.Require().NotNil(statuses[0].AppliedAt)
+	s.Require().GreaterOrEqual(statuses[0].Duration, 10*time.Millisecond)
+	s.Require().Equal(core.StatePending, statuses[1].State)
+	s.Require().Equal(core.StateOrphaned, statuses[2].State)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_Validate() {
+	migration := core.Migration{
+		Version:  "1.0.0",
+		Up:       func(ctx context.Context) error { return nil },
+		Checksum: "checksum-v1",
+	}
+	err := s.sqliteminger.ApplyMigration(s.ctx, migration)
+	s.Require().NoError(err)
+	// Edit the migration code.
+	migration.Checksum = "checksum-v2"
+	s.sqliteminger.Migrations = []core.Migration{migration}
+	mismatches, err := s.sqliteminger.Validate(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal([]core.ChecksumMismatch{{Version: "1.0.0", Recorded: "checksum-v1", Current: "checksum-v2"}}, mismatches)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_Retry() {
+	attempts := 0
+	s.sqliteminger.Migrations = []core.Migration{{
+		Version: "1.0.0",
+		Up: func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return fmt.Errorf("migration failed")
+			}
+			return nil
+		},
+		DisableTransaction: true,
+	}}
+	err := s.sqliteminger.Up(s.ctx, "")
+	s.Require().Error(err)
+	// Dirty migrations are not applied again by Up.
+	err = s.sqliteminger.Up(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal(1, attempts)
+
+	err = s.sqliteminger.Retry(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(2, attempts)
+	schema, err := s.sqliteminger.GetSchema(s.ctx, "1.0.0")
+	s.Require().NoError(err)
+	s.Require().Equal(core.Applied, schema.Status)
+}
+
+func TestSqlitemingerTestSuite(t *testing.T) {
+	suite.Run(t, new(SqlitemingerTestSuite))
+}
+
+func TestSqliteminger_Conformance(t *testing.T) {
+	plugintest.Run(t, func(t *testing.T) core.BaseMigratorAbstractMethods {
+		sqliteminger := NewSqliteminger(&core.GomigerConfig{
+			URI:         "file:" + filepath.Join(t.TempDir(), "plugintest.db"),
+			SchemaStore: "schema_migrations",
+		})
+		if err := sqliteminger.Connect(context.Background()); err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		t.Cleanup(func() { _ = sqliteminger.DB.Close() })
+		return sqliteminger
+	})
+}
@@ -0,0 +1,33 @@
+package sqliteminger
+
+import (
+	"context"
+	"fmt"
+)
+
+// Snapshot implements gomigertest.Snapshotter.
+// The state is the SQL definition of the tables, indexes, views & triggers of the database, by type & name.
+// The schema table and the internal objects of SQLite are excluded.
+func (s *Sqliteminger) Snapshot(ctx context.Context) (map[string]string, error) {
+	rows, err := s.DB.QueryContext(
+		ctx,
+		"SELECT type, name, COALESCE(sql, '') FROM sqlite_master WHERE name NOT LIKE 'sqlite_%' AND tbl_name != ?",
+		s.schemaStore,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+	snapshot := map[string]string{}
+	for rows.Next() {
+		var kind, name, definition string
+		if err := rows.Scan(&kind, &name, &definition); err != nil {
+			return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+		}
+		snapshot[kind+" "+name] = definition
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,71 @@
+package sqliteminger
+
+import (
+	"context"
+
+	"github.com/ParteeLabs/gomiger/core"
+	"github.com/ParteeLabs/gomiger/core/gomigertest"
+)
+
+var _ gomigertest.Snapshotter = (*Sqliteminger)(nil)
+
+// exec returns a migration function which runs a statement in the transaction of the migration.
+func (s *SqlitemingerTestSuite) exec(statement string) core.MutationFunc {
+	return func(ctx context.Context) error {
+		_, err := s.sqliteminger.Conn(ctx).ExecContext(ctx, statement)
+		return err
+	}
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_Snapshot() {
+	s.createUsers()
+	_, err := s.sqliteminger.DB.ExecContext(s.ctx, "CREATE UNIQUE INDEX users_name ON users (name)")
+	s.Require().NoError(err)
+
+	snapshot, err := s.sqliteminger.Snapshot(s.ctx)
+	s.Require().NoError(err)
+	// The schema table is excluded.
+	s.Require().Equal(map[string]string{
+		"table users":      "CREATE TABLE users (name TEXT)",
+		"index users_name": "CREATE UNIQUE INDEX users_name ON users (name)",
+	}, snapshot)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_Roundtrip() {
+	s.sqliteminger.Migrations = []core.Migration{
+		{
+			Version: "1.0.0",
+			Up:      s.exec("CREATE TABLE users (name TEXT)"),
+			Down:    s.exec("DROP TABLE users"),
+		},
+		{
+			Version: "2.0.0",
+			Up:      s.exec("CREATE INDEX users_name ON users (name)"),
+			Down:    s.exec("DROP INDEX users_name"),
+		},
+	}
+	err := gomigertest.Verify(s.ctx, s.sqliteminger, s.sqliteminger)
+	s.Require().NoError(err)
+}
+
+func (s *SqlitemingerTestSuite) TestSqliteminger_Roundtrip_Irreversible() {
+	s.sqliteminger.Migrations = []core.Migration{
+		{
+			Version: "1.0.0",
+			Up:      s.exec("CREATE TABLE users (name TEXT)"),
+			Down:    s.exec("DROP TABLE users"),
+		},
+		{
+			Version: "2.0.0",
+			Up:      s.exec("ALTER TABLE users ADD COLUMN email TEXT"),
+			// The down forgets to drop the column.
+			Down: func(ctx context.Context) error { return nil },
+		},
+	}
+	err := gomigertest.Verify(s.ctx, s.sqliteminger, s.sqliteminger)
+	var irreversible *gomigertest.IrreversibleError
+	s.Require().ErrorAs(err, &irreversible)
+	s.Require().Equal("2.0.0", irreversible.Version)
+	s.Require().Equal(gomigertest.StageDown, irreversible.Stage)
+	s.Require().Equal([]string{"~ table users: CREATE TABLE users (name TEXT) -> CREATE TABLE users (name TEXT, email TEXT)"}, irreversible.Diff)
+}
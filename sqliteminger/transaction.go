@@ -0,0 +1,91 @@
+package sqliteminger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ParteeLabs/gomiger/core"
+)
+
+// DBTX is the query interface shared by *sql.DB and *sql.Tx.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+var (
+	_ DBTX = (*sql.DB)(nil)
+	_ DBTX = (*sql.Tx)(nil)
+)
+
+// txKey is the context key of the transaction of a running migration.
+type txKey struct{}
+
+// Conn returns the transaction of the running migration, or the database when it runs without transaction.
+// SQLite has a single writer, so a migration which writes with the DB while its transaction is open fails as busy.
+func (s *Sqliteminger) Conn(ctx context.Context) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return s.DB
+}
+
+// inTransaction runs fn in a transaction, which is committed if fn succeeds and rolled back otherwise.
+// The context passed to fn carries the transaction, see Conn.
+func (s *Sqliteminger) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to rollback transaction: %w", rbErr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// applyMigrationInTransaction runs the migration and inserts its schema row in a single transaction.
+// A failed migration is rolled back and stays pending, it is never marked as dirty.
+func (s *Sqliteminger) applyMigrationInTransaction(ctx context.Context, mi core.Migration) error {
+	if err := s.inTransaction(ctx, func(ctx context.Context) error {
+		startedAt := time.Now()
+		if err := mi.Up(ctx); err != nil {
+			return err
+		}
+		schema := core.Schema{
+			Version:   mi.Version,
+			Status:    core.Applied,
+			Timestamp: startedAt,
+			Duration:  time.Since(startedAt),
+			Checksum:  mi.Checksum,
+		}
+		core.ExecutionFromContext(ctx).Stamp(&schema, nil)
+		return s.insertSchema(ctx, schema)
+	}); err != nil {
+		return fmt.Errorf("failed to apply migration %s, the transaction is rolled back: %w", mi.Version, err)
+	}
+	return nil
+}
+
+// revertMigrationInTransaction reverts the migration and deletes its schema row in a single transaction.
+// A failed revert is rolled back and the migration stays applied.
+func (s *Sqliteminger) revertMigrationInTransaction(ctx context.Context, mi core.Migration) error {
+	if err := s.inTransaction(ctx, func(ctx context.Context) error {
+		if err := mi.Down(ctx); err != nil {
+			return err
+		}
+		return s.DeleteSchema(ctx, mi.Version)
+	}); err != nil {
+		return fmt.Errorf("failed to revert migration %s, the transaction is rolled back: %w", mi.Version, err)
+	}
+	return nil
+}